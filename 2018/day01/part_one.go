@@ -1,65 +1,63 @@
-package main
+package day01
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
 )
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
+func init() {
+	aoc.Register(2018, 1, 1, partOne)
+}
 
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
-	if err != nil {
-		log.Fatal(err)
+// partOne calculates the resulting frequency after
+// all changes of frequency in input are applied.
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text.
-	scanner := bufio.NewScanner(inputFile)
+	// Declare scanner to read from input. Note that
+	// it skips blank lines, and each line is without
+	// surrounding whitespace and line ending.
+	scanner := parse.NewScanner(input)
 
 	// Initial frequency is zero
 	var frequency = 0
 
-	// Iterate over each line from inputFile "input.txt"
+	// Iterate over each line from input
 	for scanner.Scan() {
 		// Save current frequency, so we can print it later
 		var currentFrequency = frequency
 
-		// Line from inputFile
-		var line = scanner.Text()
+		// Line from input
+		var line = scanner.Line()
 
-		// Use Atoi to convert line (string) to int.
+		// Use Int to convert line to int.
 		//
-		// Note that Atoi takes the characters "+"
+		// Note that Int takes the characters "+"
 		// and "-" into account when converting
 		// to int.
-		//
-		// See also: https://golang.org/pkg/strconv/#hdr-Numeric_Conversions
-		i, err := strconv.Atoi(line)
+		i, err := line.Int()
 
 		if err != nil {
-			log.Fatal(err)
+			return "", err
 		}
 
 		frequency = frequency + i
 
 		// Print the changes that occur
-		fmt.Println("Current frequency", currentFrequency, "change of", line+"; resulting frequency", frequency)
+		fmt.Fprintln(output, "Current frequency", currentFrequency, "change of", line.Text+"; resulting frequency", frequency)
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	// Print the final answer
-	fmt.Println("Answer:", frequency)
+	// The final answer
+	return strconv.Itoa(frequency), nil
 }
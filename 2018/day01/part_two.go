@@ -1,11 +1,14 @@
-package main
+package day01
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
 )
 
 // contains validates if e already exists in s.
@@ -21,76 +24,78 @@ func contains(s []int, e int) bool {
 	return false
 }
 
-func main() {
+func init() {
+	aoc.Register(2018, 1, 2, partTwo)
+}
+
+// partTwo finds the first frequency our device reaches
+// twice, while it keeps repeating the changes of
+// frequency in input.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
+	}
+
+	// Read all lines from input, because we may need
+	// to iterate over them many times.
+	lines, err := parse.Lines(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(lines) == 0 {
+		return "", errors.New("no changes of frequency found")
+	}
+
+	// Convert each line to a change of frequency first,
+	// so a bad line is reported before we start.
+	changes := make([]int, len(lines))
+
+	for i, line := range lines {
+		// Use Int to convert line to int.
+		//
+		// Note that Int takes the characters "+"
+		// and "-" into account when converting
+		// to int.
+		if changes[i], err = line.Int(); err != nil {
+			return "", err
+		}
+	}
+
 	// Store all reached frequencies in this slice
 	var reachedFrequencies []int
 
-	// Keep track if we have not found the first
-	// frequency our device reaches twice
-	var notFound = true
-
 	// Initial frequency is zero
 	var frequency = 0
 
 	// Keep iterating as long as we have not found the
 	// first frequency our device reaches twice.
-	for notFound {
-		// Open file "input.txt" for reading
-		inputFile, err := os.Open("input.txt")
-
-		// Opening file can have an error. If there is
-		// an error, it will be of type *PathError.
-		if err != nil {
-			log.Fatal(err)
+	for {
+		// If no frequency is ever reached twice, we
+		// keep iterating until we are stopped.
+		if err := ctx.Err(); err != nil {
+			return "", err
 		}
 
-		// Closes the file when we are done
-		defer inputFile.Close()
-
-		// Declare scanner to read from inputFile. Note that
-		// the split function defaults to ScanLines—which
-		// is each line of text.
-		scanner := bufio.NewScanner(inputFile)
-
-		// Iterate over each line from inputFile "input.txt"
-		for scanner.Scan() {
+		for i, change := range changes {
 			// Save current frequency, so we can print it later
 			var currentFrequency = frequency
 
-			// Line from inputFile
-			var line = scanner.Text()
-
-			// Use Atoi to convert line (string) to int.
-			//
-			// Note that Atoi takes the characters "+"
-			// and "-" into account when converting
-			// to int.
-			//
-			// See also: https://golang.org/pkg/strconv/#hdr-Numeric_Conversions
-			i, err := strconv.Atoi(line)
-
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			frequency = frequency + i
+			frequency = frequency + change
 
 			// Print the changes that occur
-			fmt.Println("Current frequency", currentFrequency, "change of", line+"; resulting frequency", frequency)
+			fmt.Fprintln(output, "Current frequency", currentFrequency, "change of", lines[i].Text+"; resulting frequency", frequency)
 
 			// Check if this frequency already was reached once
 			if contains(reachedFrequencies, frequency) {
-				fmt.Println("The first frequency our device reaches twice is", frequency)
-				notFound = false
-				break
+				fmt.Fprintln(output, "The first frequency our device reaches twice is", frequency)
+
+				return strconv.Itoa(frequency), nil
 			}
 
 			// Add this frequency to reachedFrequencies
 			reachedFrequencies = append(reachedFrequencies, frequency)
 		}
-
-		if err := scanner.Err(); err != nil {
-			log.Fatal(err)
-		}
 	}
 }
@@ -1,40 +1,40 @@
-package main
+package day02
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
 	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
 )
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
+func init() {
+	aoc.Register(2018, 2, 1, partOne)
+}
 
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
-	if err != nil {
-		log.Fatal(err)
+// partOne calculates the checksum of the box IDs in input
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text.
-	scanner := bufio.NewScanner(inputFile)
+	// Declare scanner to read from input. Note that
+	// it skips blank lines, and each line is without
+	// surrounding whitespace and line ending.
+	scanner := parse.NewScanner(input)
 
 	// Keep track of how many times two of any letter and
 	// three of any letter occurs
 	var twoOfAnyLetter = 0
 	var threeOfAnyLetter = 0
 
-	// Iterate over each line from inputFile "input.txt"
+	// Iterate over each line from input
 	for scanner.Scan() {
-		// Line from inputFile
-		var id = scanner.Text()
+		// Line from input
+		var id = scanner.Line().Text
 
 		// If a letter appears two or three times in this id,
 		// we must only count it once. So we keep track if
@@ -58,7 +58,7 @@ func main() {
 			// Count how many times this letter appears in this id
 			var count = strings.Count(id, letter)
 
-			fmt.Println("Letter", letter, "appears", count, "times in ID", id)
+			fmt.Fprintln(output, "Letter", letter, "appears", count, "times in ID", id)
 
 			// If containsThreeOfAnyLetter is false
 			if !containsThreeOfAnyLetter && count == 3 {
@@ -85,18 +85,20 @@ func main() {
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
 	// Multiplying twoOfAnyLetter and threeOfAnyLetter
 	// produces a checksum
 	var checksum = twoOfAnyLetter * threeOfAnyLetter
 
-	// Print the final answer
-	fmt.Println("Of these box IDs,",
+	fmt.Fprintln(output, "Of these box IDs,",
 		twoOfAnyLetter,
 		"of them contain a letter which appears exactly twice, and",
 		threeOfAnyLetter,
 		"of them contain a letter which appears exactly three times. Multiplying these together produces a checksum of",
 		checksum)
+
+	// The final answer
+	return strconv.Itoa(checksum), nil
 }
@@ -1,41 +1,39 @@
-package main
+package day02
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
 )
 
+func init() {
+	aoc.Register(2018, 2, 2, partTwo)
+}
+
+// errNoMatch is returned by compareIDs if no ID
+// differs by exactly one character.
+var errNoMatch = errors.New("did not find an ID that differs by exactly one character")
+
 // compareIDs compares each character of this id
-// with each character of eacg ID from input.txt.
+// with each character of each ID of ids.
 //
 // If an ID is found that differs by only one
 // character from this id, we return the
 // index of that character.
-func compareIDs(id string) (int, error) {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
-
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text.
-	scanner := bufio.NewScanner(inputFile)
-
-	// Iterate over each line from inputFile "input.txt"
-	for scanner.Scan() {
-		// Line from inputFile
-		var idToCompare = scanner.Text()
+func compareIDs(id string, ids []parse.Line) (int, error) {
+	// Iterate over each ID
+	for _, line := range ids {
+		var idToCompare = line.Text
+
+		// IDs of a different length can never
+		// differ by only one character.
+		if len(idToCompare) != len(id) {
+			continue
+		}
 
 		// Keep track how many letters mismatch
 		var mismatchedLetters = 0
@@ -66,68 +64,49 @@ func compareIDs(id string) (int, error) {
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	return 0, errors.New("Did not found ID that differs by exactly one character")
+	return 0, errNoMatch
 }
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
-
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
-	if err != nil {
-		log.Fatal(err)
+// partTwo finds the letters that are common between
+// the two correct box IDs in input.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
+	// Read all IDs from input, because we compare
+	// each ID with each other ID.
+	ids, err := parse.Lines(input)
 
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text.
-	scanner := bufio.NewScanner(inputFile)
-
-	var commonLetters = ""
+	if err != nil {
+		return "", err
+	}
 
-	// Iterate over each line from inputFile "input.txt"
-	for scanner.Scan() {
-		// Line from inputFile
-		var id = scanner.Text()
+	// Iterate over each ID
+	for _, line := range ids {
+		var id = line.Text
 
-		var indexOfMismatchedLetter, err = compareIDs(id)
+		var indexOfMismatchedLetter, err = compareIDs(id, ids)
 
 		// If compareIDs has no error, it means that a ID
 		// is found with only one character different
 		// from this id.
-		if err == nil {
-			fmt.Println("Index of mismatched letter:", indexOfMismatchedLetter)
-
-			// If indexOfMismatchedLetter is the first OR last
-			// charachter of this id, we must substring it
-			// differently to prevent index out of range
-			// and other errors.
-			if indexOfMismatchedLetter == 0 {
-				commonLetters = id[1:len(id)]
-			} else if indexOfMismatchedLetter == len(id)-1 {
-				commonLetters = id[0 : len(id)-2]
-			} else {
-				commonLetters = id[0:indexOfMismatchedLetter] + id[indexOfMismatchedLetter+1:len(id)]
-			}
-
-			// We have found the commonLetters, so we can
-			// exit this loop.
-			break
+		if err != nil {
+			continue
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		fmt.Fprintln(output, "Index of mismatched letter:", indexOfMismatchedLetter)
+
+		// The common letters are all letters
+		// before and after the mismatched
+		// letter.
+		var commonLetters = id[:indexOfMismatchedLetter] + id[indexOfMismatchedLetter+1:]
+
+		fmt.Fprintln(output, "The following letters are common between the two correct box IDs:", commonLetters)
+
+		// The final answer
+		return commonLetters, nil
 	}
 
-	// Print the final answer
-	fmt.Println("The following letters are common between the two correct box IDs:", commonLetters)
+	return "", errNoMatch
 }
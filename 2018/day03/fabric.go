@@ -0,0 +1,103 @@
+// Package day03 solves the puzzle of day 3, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 3
+package day03
+
+import (
+	"io"
+
+	"github.com/TonnyGaric/adventofcode/internal/grid"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+type claim struct {
+	ID                 int
+	inchesFromLeftEdge int
+	inchesFromTopEdge  int
+	inchesWide         int
+	inchesTall         int
+}
+
+// A claim consists of a pattern as follows:
+//
+//	#13 @ 176,605: 24x11
+//	 ^^   ^^^ ^^^  ^^ ^^
+//	 1     2   3   4  5
+//
+// Where:
+// 1 is the claim ID
+// 2 is the inches from the left edge
+// 3 is the inches from the top edge
+// 4 is the inches wide
+// 5 is the inches tall
+var claimPattern = parse.MustCompile(`^#(\d+) @ (\d+),(\d+): (\d+)x(\d+)$`, "#13 @ 176,605: 24x11")
+
+// parseClaim parses the claim on this line
+func parseClaim(line parse.Line) (claim, error) {
+	numbers, err := claimPattern.Ints(line)
+
+	if err != nil {
+		return claim{}, err
+	}
+
+	return claim{
+		ID:                 numbers[0],
+		inchesFromLeftEdge: numbers[1],
+		inchesFromTopEdge:  numbers[2],
+		inchesWide:         numbers[3],
+		inchesTall:         numbers[4]}, nil
+}
+
+// readClaims reads all claims from reader,
+// one claim per line.
+func readClaims(reader io.Reader) ([]claim, error) {
+	return parse.Map(reader, parseClaim)
+}
+
+// newFabric creates the piece of fabric, where each
+// square inch holds the number of these claims that
+// are within it.
+//
+// According to the README, the fabric is a very large
+// square, with at least 1000 inches on each side. So
+// the fabric starts with 1000 inches on each side,
+// and grows to fit all claims.
+func newFabric(claims []claim) *grid.Grid[int] {
+	bottomRight := grid.Point{X: 999, Y: 999}
+
+	for _, c := range claims {
+		bottomRight.X = max(bottomRight.X, c.inchesFromLeftEdge+c.inchesWide-1)
+		bottomRight.Y = max(bottomRight.Y, c.inchesFromTopEdge+c.inchesTall-1)
+	}
+
+	fabric := grid.New[int](grid.Point{X: 0, Y: 0}, bottomRight)
+
+	for _, c := range claims {
+		// The square inches of this claim. This shares
+		// its square inches with the fabric, so we can
+		// change the fabric through it.
+		area := fabric.Sub(c.topLeft(), c.bottomRight())
+
+		for squareInch, claimsWithin := range area.All() {
+			// Increment this square of inch,
+			// so we now how many claims are
+			// within this square inch.
+			area.Set(squareInch, claimsWithin+1)
+		}
+	}
+
+	return fabric
+}
+
+// topLeft returns the top left square inch of claim c
+func (c claim) topLeft() grid.Point {
+	return grid.Point{X: c.inchesFromLeftEdge, Y: c.inchesFromTopEdge}
+}
+
+// bottomRight returns the bottom right square
+// inch of claim c.
+func (c claim) bottomRight() grid.Point {
+	return grid.Point{X: c.inchesFromLeftEdge + c.inchesWide - 1, Y: c.inchesFromTopEdge + c.inchesTall - 1}
+}
@@ -1,194 +1,63 @@
-package main
+package day04
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"log"
-	"os"
-	"sort"
+	"io"
 	"strconv"
-	"strings"
-	"time"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
 )
 
-// parseTime parses this line to Time, with the format:
-// "2006-01-02 15:04".
-//
-// Example how this line can look like:
-//
-// [1518-04-09 00:46] wakes up
-//  ^              ^
-//  1              16
-//
-// We can expect the first 18 charachters of each line
-// to be of the same pattern.
-func parseTime(line string) (time.Time, error) {
-	return time.Parse("2006-01-02 15:04", line[1:17])
+func init() {
+	aoc.Register(2018, 4, 1, partOne)
 }
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
-
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
-	if err != nil {
-		log.Fatal(err)
+// partOne finds the guard that has the most minutes
+// asleep, and the minute he spends asleep the most.
+// The answer is the ID of the guard multiplied by
+// the minute.
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text.
-	scanner := bufio.NewScanner(inputFile)
-
-	// We will append all lines from inputFile to this
-	// slice, so we can later sort this slice to
-	// chronological order.
-	var lines = []string{}
-
-	// Iterate over each line from inputFile "input.txt",
-	// and append it to the slice lines.
-	for scanner.Scan() {
-		// Line from inputFile
-		line := scanner.Text()
+	// Read each nap of each guard, so we can later
+	// count how many times each guard slept on
+	// each minute.
+	naps, err := readNaps(input)
 
-		lines = append(lines, line)
+	if err != nil {
+		return "", err
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+	table := newMinuteTable(naps)
 
-	// Sort slice to have all lines in chronological order,
-	// so we can later analyize it.
-	sort.Strings(lines)
-
-	// Make a map where:
-	// - key: ID of guard
-	// - value: minutes this guard is sleeping
-	minutesSleptPerGuard := make(map[int]int)
-
-	// Make a map where:
-	// - key: ID of guard
-	// - value: map where:
-	//     - key: minute of hour this guard slept
-	//     - value: times this guard slept on
-	//              this minute of hour
-	minutesSleptPerMinuteOfHour := make(map[int]map[int]int)
-
-	// Keep track of the ID of the last processed guard.
-	// So we can map the time of woke up and fell asleep
-	// to this guard.
-	var idOfLastGuard int
-
-	// Keep track of the ID of the guard that slept the
-	// most minutes in total, compared to all other
-	// guards.
-	var idOfGuardThatSleepsMostMinutes int
+	// Keep track of the row of the guard that slept
+	// the most minutes in total, compared to all
+	// other guards.
+	var rowOfGuardThatSleepsMostMinutes int
 
 	// Keep track of the total minutes slept by the guard
 	// that slept the most minutes in total, compared to
 	// all other guards.
 	var mostMinutesSleeping int
 
-	// Iterate over each line in the chronologically
-	// ordered slice lines and analyse each line.
-	for i := 0; i < len(lines); i++ {
-		line := lines[i]
-
-		// We can expect three types of lines.
-		// Each line, has a unique suffix.
-		//
-		// [1518-03-12 00:04] Guard #1987 begins shift
-		//                               ^^^^^^^^^^^^^
-		//								 unique suffix
-		//
-		// [1518-03-12 00:21] falls asleep
-		//                  ^^^^^^^^^^^^^^
-		//					unique suffix
-		//
-		// [1518-03-12 00:55] wakes up
-		//					^^^^^^^^^^
-		//				    unique suffix
-
-		if strings.HasSuffix(line, " begins shift") {
-			// Parse ID of guard
-			guardID, err := strconv.Atoi(line[strings.Index(line, "#")+1 : strings.Index(line, "b")-1])
-
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			// Keep track of last ID of guard,
-			// so we can later match sleeping
-			// minutes to this guard.
-			idOfLastGuard = guardID
-
-			// Check if guard is present in the maps:
-			// - minutesSleptPerGuard;
-			// - minutesSleptPerMinuteOfHour.
-			if _, prs := minutesSleptPerGuard[guardID]; !prs {
-				// Guard is not present in map minutesSleptPerGuard,
-				// so add him.
-				minutesSleptPerGuard[guardID] = 0
-
-				// If guard is not present in map minutesSleptPerGuard,
-				// it means that guard is also not present in map
-				// minutesSleptPerMinuteOfHour, so add him.
-				minutesSleptPerMinuteOfHour[guardID] = make(map[int]int)
-			}
-		} else if strings.HasSuffix(line, "] falls asleep") {
-			// Parse time that guard fell asleep
-			fellAsleepTime, err := parseTime(line)
-
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			// Next line must be time that guard woke up
-			wokeUpLine := lines[i+1]
-
-			// Parse time that guard woke up
-			wokeUpTime, err := parseTime(wokeUpLine)
-
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			// Calculate total minutes that guard was sleeping
-			minutesSleeping := int(wokeUpTime.Sub(fellAsleepTime).Minutes())
-
-			// Keep track of how many minutes guard slept in total,
-			// by updating the value in map minutesSleptPerGuard.
-			minutesSleptPerGuard[idOfLastGuard] = minutesSleptPerGuard[idOfLastGuard] + minutesSleeping
-
-			for i := 0; i < minutesSleeping; i++ {
-				// fellAsleepTime.Minute() is the first
-				// minute that guard fell asleep.
-				minuteOfHour := fellAsleepTime.Minute() + i
-
-				// Keep track how many minutes guard slept
-				// on this minuteOfHour, by updating the
-				// value in map minutesSleptPerMinuteOfHour.
-				minutesSleptPerMinuteOfHour[idOfLastGuard][minuteOfHour] = minutesSleptPerMinuteOfHour[idOfLastGuard][minuteOfHour] + 1
-			}
-
-			// Keep track of guard that sleeps the most minutes
-			if minutesSleptPerGuard[idOfLastGuard] > mostMinutesSleeping {
-				idOfGuardThatSleepsMostMinutes = idOfLastGuard
-				mostMinutesSleeping = minutesSleptPerGuard[idOfLastGuard]
-			}
+	for row := range table.guardIDs {
+		minutesSleeping := 0
+
+		for _, timesSlept := range table.guard(row).All() {
+			minutesSleeping += timesSlept
 		}
 
-		// We basically ignore the line that has
-		// the suffix "] falls asleep", because
-		// we already handle that line in the
-		// `else if` statement.
+		if minutesSleeping > mostMinutesSleeping {
+			rowOfGuardThatSleepsMostMinutes = row
+			mostMinutesSleeping = minutesSleeping
+		}
 	}
 
+	idOfGuardThatSleepsMostMinutes := table.guardIDs[rowOfGuardThatSleepsMostMinutes]
+
 	// Minute of the hour that the guard that
 	// slept most minutes slept on.
 	var mostSleptMinuteOfHour int
@@ -199,16 +68,18 @@ func main() {
 
 	// Determine on what minute of hour the guard
 	// that slept most minutes slept most on.
-	for sleptMinute, sleptMinutes := range minutesSleptPerMinuteOfHour[idOfGuardThatSleepsMostMinutes] {
+	for sleptMinute, sleptMinutes := range table.guard(rowOfGuardThatSleepsMostMinutes).All() {
 		if sleptMinutes > mostSleptMinutes {
 			mostSleptMinutes = sleptMinutes
-			mostSleptMinuteOfHour = sleptMinute
+			mostSleptMinuteOfHour = sleptMinute.X
 		}
 	}
 
 	// This will be our final answer
 	answer := idOfGuardThatSleepsMostMinutes * mostSleptMinuteOfHour
 
-	// Print the final answer
-	fmt.Println(answer, "is the ID of the guard (", idOfGuardThatSleepsMostMinutes, ") we chose multiplied by the minute (", mostSleptMinuteOfHour, ") we chose.")
+	fmt.Fprintln(output, answer, "is the ID of the guard (", idOfGuardThatSleepsMostMinutes, ") we chose multiplied by the minute (", mostSleptMinuteOfHour, ") we chose.")
+
+	// The final answer
+	return strconv.Itoa(answer), nil
 }
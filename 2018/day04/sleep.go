@@ -0,0 +1,214 @@
+// Package day04 solves the puzzle of day 4, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 4
+package day04
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/TonnyGaric/adventofcode/internal/grid"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+// record is a line of the notes of the Elf, about a
+// guard that begins his shift, falls asleep or wakes
+// up at some time.
+type record struct {
+	line parse.Line
+	time time.Time
+
+	// One of beginsShift, fallsAsleep and wakesUp
+	event string
+
+	// ID of the guard that begins his shift. Other
+	// events do not name the guard, so this is 0.
+	guardID int
+}
+
+// The events a record can be about
+const (
+	beginsShift = "begins shift"
+	fallsAsleep = "falls asleep"
+	wakesUp     = "wakes up"
+)
+
+// We can expect three types of lines:
+//
+//	[1518-03-12 00:04] Guard #1987 begins shift
+//	[1518-03-12 00:21] falls asleep
+//	[1518-03-12 00:55] wakes up
+//
+// The time has the format "2006-01-02 15:04".
+var recordPattern = parse.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\] (?:Guard #(\d+) (begins shift)|(falls asleep)|(wakes up))$`, "[1518-03-12 00:04] Guard #1987 begins shift")
+
+// parseRecord parses the record on this line
+func parseRecord(line parse.Line) (record, error) {
+	groups, err := recordPattern.Match(line)
+
+	if err != nil {
+		return record{}, err
+	}
+
+	r := record{line: line}
+
+	if r.time, err = time.Parse("2006-01-02 15:04", groups[0]); err != nil {
+		return record{}, line.Errorf("invalid time %q", groups[0])
+	}
+
+	switch {
+	case groups[2] != "":
+		r.event = beginsShift
+
+		if r.guardID, err = line.Atoi(groups[1], "ID of guard"); err != nil {
+			return record{}, err
+		}
+	case groups[3] != "":
+		r.event = fallsAsleep
+	default:
+		r.event = wakesUp
+	}
+
+	return r, nil
+}
+
+// nap is a period in which a guard was asleep, from
+// the minute he fell asleep up to, but not including,
+// the minute he woke up.
+type nap struct {
+	guardID      int
+	fellAsleepAt int
+	wokeUpAt     int
+}
+
+// readNaps reads the records from reader, and returns
+// the naps of all guards in chronological order.
+//
+// The records in reader are in no particular order, so
+// we first sort them to chronological order. Each
+// guard falls asleep and wakes up only during his
+// own shift.
+func readNaps(reader io.Reader) ([]nap, error) {
+	records, err := parse.Map(reader, parseRecord)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort records to have them in chronological order,
+	// so we can later analyize them. Records at the
+	// same time keep the order of their lines.
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].time.Before(records[j].time)
+	})
+
+	var naps []nap
+
+	// Keep track of the ID of the last processed guard.
+	// So we can map the time of woke up and fell asleep
+	// to this guard.
+	var idOfLastGuard int
+
+	// Record of the last time the guard fell asleep,
+	// or nil if he is awake.
+	var fellAsleep *record
+
+	for i, r := range records {
+		switch r.event {
+		case beginsShift:
+			if fellAsleep != nil {
+				return nil, fellAsleep.line.Errorf("guard #%d falls asleep, but never wakes up before the next shift", idOfLastGuard)
+			}
+
+			idOfLastGuard = r.guardID
+		case fallsAsleep:
+			if idOfLastGuard == 0 {
+				return nil, r.line.Errorf("a guard falls asleep before any guard begins his shift")
+			}
+
+			if fellAsleep != nil {
+				return nil, r.line.Errorf("guard #%d falls asleep, but is already asleep", idOfLastGuard)
+			}
+
+			fellAsleep = &records[i]
+		case wakesUp:
+			if fellAsleep == nil {
+				return nil, r.line.Errorf("a guard wakes up, but nobody is asleep")
+			}
+
+			// A guard is only asleep during the
+			// midnight hour.
+			if fellAsleep.time.Hour() != 0 || r.time.Hour() != 0 || r.time.Sub(fellAsleep.time) >= time.Hour {
+				return nil, r.line.Errorf("guard #%d sleeps outside the midnight hour", idOfLastGuard)
+			}
+
+			naps = append(naps, nap{guardID: idOfLastGuard, fellAsleepAt: fellAsleep.time.Minute(), wokeUpAt: r.time.Minute()})
+
+			fellAsleep = nil
+		}
+	}
+
+	if fellAsleep != nil {
+		return nil, fellAsleep.line.Errorf("guard #%d falls asleep, but never wakes up", idOfLastGuard)
+	}
+
+	if len(naps) == 0 {
+		return nil, fmt.Errorf("no guard ever falls asleep")
+	}
+
+	return naps, nil
+}
+
+// minuteTable holds how many times each guard slept on
+// each minute of the midnight hour. Each row belongs
+// to a guard, and each column is a minute of the hour.
+type minuteTable struct {
+	// ID of the guard of each row
+	guardIDs []int
+
+	timesSlept *grid.Grid[int]
+}
+
+// newMinuteTable creates a minuteTable from these naps.
+// Guards get a row in the order in which they first
+// took a nap.
+func newMinuteTable(naps []nap) *minuteTable {
+	// Make a map where:
+	// - key: ID of guard
+	// - value: row of this guard
+	rows := make(map[int]int)
+
+	var guardIDs []int
+
+	for _, n := range naps {
+		if _, prs := rows[n.guardID]; !prs {
+			rows[n.guardID] = len(guardIDs)
+			guardIDs = append(guardIDs, n.guardID)
+		}
+	}
+
+	// A guard is only asleep during the midnight hour,
+	// so we only need the minutes 0 up to and
+	// including 59.
+	timesSlept := grid.New[int](grid.Point{X: 0, Y: 0}, grid.Point{X: 59, Y: len(guardIDs) - 1})
+
+	for _, n := range naps {
+		for minuteOfHour := n.fellAsleepAt; minuteOfHour < n.wokeUpAt; minuteOfHour++ {
+			p := grid.Point{X: minuteOfHour, Y: rows[n.guardID]}
+
+			timesSlept.Set(p, timesSlept.Get(p)+1)
+		}
+	}
+
+	return &minuteTable{guardIDs: guardIDs, timesSlept: timesSlept}
+}
+
+// guard returns the minutes of the midnight hour
+// of the guard in this row.
+func (t *minuteTable) guard(row int) *grid.Grid[int] {
+	return t.timesSlept.Sub(grid.Point{X: 0, Y: row}, grid.Point{X: 59, Y: row})
+}
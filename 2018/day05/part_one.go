@@ -1,63 +1,85 @@
-package main
+package day05
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"strings"
+	"io"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
 )
 
-func main() {
-	// Slurp the entire content of "input.txt"
-	// into our memory.
-	inputData, err := ioutil.ReadFile("input.txt")
+func init() {
+	aoc.Register(2018, 5, 1, partOne)
+}
+
+// partOne fully reacts the polymer in input, and
+// returns how many units remain.
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 5 part 1", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// By default, units react according to the puzzle.
+	// Optionally, a file with other reacting pairs can
+	// be passed. See parseRuleSet in polymer.go for
+	// its format.
+	rulesPath := flags.String("rules", "", "path of a file with reacting pairs of units")
+
+	// Optionally, print which units reacted. For small
+	// polymers, this prints each intermediate polymer.
+	// For large polymers, it prints summary statistics.
+	traceReactions := flags.Bool("trace", false, "print the reactions of the polymer")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	rules, err := loadRuleSet(*rulesPath)
 
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	// inputData is of type []byte, we must convert
-	// it to string.
-	polymer := string(inputData)
-
-	// Length of polymer when the for loop starts
-	oldPolymerLength := len(polymer)
-
-	// Length of polymer after the for loop finishes.
-	//
-	// We will start with 0, after the first loop,
-	// we have the new accurate length.
-	newPolymerLength := 0
-
-	// We will keep iterating, as long as the
-	// length of polymer keeps changing.
-	for oldPolymerLength != newPolymerLength {
-		oldPolymerLength = len(polymer)
-
-		// We need to remove all occurances in polymer
-		// of "aA" and "Aa", for all characters of the
-		// alphabet.
-		//
-		// According to Wikipedia, the upper case
-		// alphabet characters are ASCII 65 (A)
-		// up to and including 90 (Z)
-		//
-		// See: https://en.wikipedia.org/wiki/ASCII
-		for i := 65; i <= 90; i++ {
-			upperChar := string(i)
-			lowerChar := strings.ToLower(upperChar)
-
-			// -1 means that we will replace all occurances
-			polymer = strings.Replace(polymer, upperChar+lowerChar, "", -1)
-			polymer = strings.Replace(polymer, lowerChar+upperChar, "", -1)
+	// Fully react the polymer while we read it from
+	// input, so we never need the entire polymer
+	// in our memory. See polymer.go for how the
+	// reaction is done.
+	var trace *reactionTrace
+
+	if *traceReactions {
+		// Printing each intermediate polymer is only
+		// readable for polymers of up to 100 units.
+		trace = newReactionTrace(100)
+	}
+
+	polymer, err := rules.reactReader(ctx, input, trace)
+
+	if err != nil {
+		return "", err
+	}
+
+	if trace != nil {
+		lines, ok := trace.replay()
+
+		if !ok {
+			lines = trace.summary(rules)
 		}
 
-		newPolymerLength = len(polymer)
+		for _, line := range lines {
+			fmt.Fprintln(output, line)
+		}
 	}
 
 	// This will be our final answer
 	answer := len(polymer)
 
-	// Print final answer
-	fmt.Println(answer, "units remain after fully reacting the polymer we scanned.")
+	fmt.Fprintln(output, answer, "units remain after fully reacting the polymer we scanned.")
+
+	// The final answer
+	return strconv.Itoa(answer), nil
 }
@@ -1,98 +1,175 @@
-package main
+package day05
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"strings"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
 )
 
-func main() {
-	// Slurp the entire content of "input.txt"
-	// into our memory.
-	inputData, err := ioutil.ReadFile("input.txt")
+// reduction is the result of removing all units
+// of one type from a polymer and fully reacting
+// the result.
+type reduction struct {
+	unitType [2]rune
+	length   int
+}
 
-	if err != nil {
-		log.Fatal(err)
-	}
+// removeUnitType returns a copy of this polymer
+// without any unit of this unitType, regardless
+// of its polarity.
+func removeUnitType(polymer []rune, unitType [2]rune) []rune {
+	result := make([]rune, 0, len(polymer))
 
-	// inputData is of type []byte, we must convert
-	// it to string.
-	polymer := string(inputData)
+	for _, unit := range polymer {
+		if unit != unitType[0] && unit != unitType[1] {
+			result = append(result, unit)
+		}
+	}
 
-	shortestPolymerLength := len(polymer)
+	return result
+}
 
-	// We need to remove all occurances in polymer
-	// of "a" and "A", for all characters of the
-	// alphabet, then apply some logic to the
-	// modified polymer.
-	//
-	// According to Wikipedia, the upper case
-	// alphabet characters are ASCII 65 (A)
-	// up to and including 90 (Z)
-	//
-	// See: https://en.wikipedia.org/wiki/ASCII
-	for i := 65; i <= 90; i++ {
-		// Create a temporary polymer based on polymer,
-		// so the original polymer stays preserved and
-		// we start each iteration with the orginal
-		// polymer.
-		tempPolymer := polymer
-
-		// The character we will remove from the tempPolymer
-		upperCharToRemove := string(i)
-
-		// Also remove the lower case variant
-		// of charToRemove from tempPolymer
-		lowerCharToRemove := strings.ToLower(upperCharToRemove)
-
-		// Remove the upperCharToRemove and lowerCharToRemove
-		// from tempPolymer
-		tempPolymer = strings.Replace(tempPolymer, upperCharToRemove, "", -1)
-		tempPolymer = strings.Replace(tempPolymer, lowerCharToRemove, "", -1)
-
-		// Length of tempPolymer when the for loop starts
-		oldPolymerLength := len(tempPolymer)
-
-		// Length of tempPolymer after the for loop finishes.
-		//
-		// We will start with 0, after the first loop,
-		// we have the new accurate length.
-		newPolymerLength := 0
-
-		// We will keep iterating, as long as the
-		// length of tempPolymer keeps changing.
-		for oldPolymerLength != newPolymerLength {
-			oldPolymerLength = len(tempPolymer)
-
-			// We need to remove all occurances in tempPolymer
-			// of "aA" and "Aa", for all characters of the
-			// alphabet.
-			//
-			// According to Wikipedia, the upper case
-			// alphabet characters are ASCII 65 (A)
-			// up to and including 90 (Z)
-			//
-			// See: https://en.wikipedia.org/wiki/ASCII
-			for i := 65; i <= 90; i++ {
-				upperChar := string(i)
-				lowerChar := strings.ToLower(upperChar)
-
-				// -1 means that we will replace all occurances
-				tempPolymer = strings.Replace(tempPolymer, upperChar+lowerChar, "", -1)
-				tempPolymer = strings.Replace(tempPolymer, lowerChar+upperChar, "", -1)
+// reduceEachType removes each unit type of these rules
+// from this polymer, fully reacts the result and returns
+// the reductions in the order of the types in rules,
+// together with the reduction that produces the
+// shortest polymer.
+//
+// The unit types are evaluated concurrently by at most
+// workers goroutines. Each goroutine writes only to the
+// element of reductions that belongs to its unit type,
+// so the order of reductions does not depend on which
+// goroutine finishes first.
+//
+// Once ctx is done, no more unit types are handed out.
+// We wait for the types that are being evaluated, and
+// return the error of ctx.
+func reduceEachType(ctx context.Context, rules *ruleSet, polymer []rune, workers int) ([]reduction, reduction, error) {
+	reductions := make([]reduction, len(rules.types))
+
+	// Each goroutine receives the index of the
+	// type in rules it should evaluate.
+	typeIndexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range typeIndexes {
+				unitType := rules.types[i]
+
+				reductions[i] = reduction{
+					unitType: unitType,
+					length:   len(rules.react(removeUnitType(polymer, unitType)))}
 			}
+		}()
+	}
 
-			newPolymerLength = len(tempPolymer)
+	for i := range rules.types {
+		if ctx.Err() != nil {
+			break
 		}
 
-		if newPolymerLength < shortestPolymerLength {
-			shortestPolymerLength = newPolymerLength
+		typeIndexes <- i
+	}
+
+	close(typeIndexes)
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, reduction{}, err
+	}
+
+	// Determine the shortest reduction. On a tie,
+	// the first unit type in rules wins. If rules
+	// has no types at all, nothing can be removed
+	// and the polymer keeps its length.
+	shortest := reduction{length: len(polymer)}
+
+	for i, reduction := range reductions {
+		if i == 0 || reduction.length < shortest.length {
+			shortest = reduction
 		}
+	}
+
+	return reductions, shortest, nil
+}
 
-		fmt.Println("Removing all units of "+upperCharToRemove+"/"+lowerCharToRemove+" and fully reacting the result, produces a polymer with a length of", newPolymerLength)
+func init() {
+	aoc.Register(2018, 5, 2, partTwo)
+}
+
+// partTwo finds the length of the shortest polymer we
+// can produce, by removing all units of exactly one
+// type from the polymer in input and fully reacting
+// the result.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 5 part 2", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// By default, units react according to the puzzle.
+	// Optionally, a file with other reacting pairs can
+	// be passed. See parseRuleSet in polymer.go for
+	// its format.
+	rulesPath := flags.String("rules", "", "path of a file with reacting pairs of units")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	rules, err := loadRuleSet(*rulesPath)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Removing all units of a type and then fully
+	// reacting the result, gives the same length as
+	// first fully reacting the polymer, then removing
+	// all units of a type and fully reacting again.
+	// Pairs that reacted before the removal would
+	// still react after it. So we react the polymer
+	// once up front, which makes each reduction work
+	// on a much shorter polymer.
+	//
+	// We react the polymer while we read it from
+	// input, so we never need the entire
+	// polymer in our memory.
+	polymer, err := rules.reactReader(ctx, input, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Evaluate the unit types with one goroutine
+	// per CPU.
+	reductions, shortest, err := reduceEachType(ctx, rules, polymer, runtime.NumCPU())
+
+	if err != nil {
+		return "", err
 	}
 
-	// Print final answer
-	fmt.Println(shortestPolymerLength, "is the length of the shortest polymer we can remove by removing all units of exactly one type and fully reacting the result.")
+	for _, reduction := range reductions {
+		fmt.Fprintln(output, "Removing all units of "+typeName(reduction.unitType)+" and fully reacting the result, produces a polymer with a length of", reduction.length)
+	}
+
+	fmt.Fprintln(output, shortest.length, "is the length of the shortest polymer we can remove by removing all units of exactly one type and fully reacting the result.")
+
+	// The final answer
+	return strconv.Itoa(shortest.length), nil
 }
@@ -0,0 +1,514 @@
+// Package day05 solves the puzzle of day 5, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 5
+//
+// Part one accepts the flags -rules and -trace, see
+// part_one.go. For example:
+//
+//	go run ./cmd/aoc run 2018 5 1 -trace
+package day05
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+// ruleSet describes which units of a polymer react
+// with each other.
+//
+// Each reacting pair of units forms a type, e.g. "A"
+// and "a" are both of type "A/a". A unit can be part
+// of only one pair, so for each unit there is at most
+// one other unit it reacts with. Units that are not
+// part of any pair never react.
+type ruleSet struct {
+	// Each element is a reacting pair of units, in
+	// the order they were added to this ruleSet.
+	types [][2]rune
+
+	// Map where:
+	// - key: unit
+	// - value: the unit that reacts with key
+	partners map[rune]rune
+
+	// Map where:
+	// - key: unit
+	// - value: index in types of the type of key
+	typeIndexes map[rune]int
+
+	// Looking up a unit in partners is a lot slower
+	// than indexing an array. Most polymers only
+	// consist of ASCII units, so we also keep the
+	// partner of each ASCII unit in this array,
+	// where the index is the unit. If an ASCII
+	// unit has no partner, its value is -1.
+	asciiPartners [128]rune
+}
+
+// newRuleSet creates a ruleSet without any
+// reacting pairs.
+func newRuleSet() *ruleSet {
+	rules := &ruleSet{
+		partners:    make(map[rune]rune),
+		typeIndexes: make(map[rune]int)}
+
+	for i := range rules.asciiPartners {
+		rules.asciiPartners[i] = -1
+	}
+
+	return rules
+}
+
+// defaultRuleSet creates the ruleSet of the puzzle:
+// each upper case letter "A" up to and including
+// "Z" reacts with its lower case counterpart.
+func defaultRuleSet() *ruleSet {
+	rules := newRuleSet()
+
+	for upperUnit := 'A'; upperUnit <= 'Z'; upperUnit++ {
+		// Letters of the alphabet can never be
+		// added twice, so we can safely ignore
+		// the error.
+		_ = rules.addPair(upperUnit, unicode.ToLower(upperUnit))
+	}
+
+	return rules
+}
+
+// addPair adds the pair of unit a and unit b, that react
+// with each other, as a new type to this ruleSet.
+func (rules *ruleSet) addPair(a rune, b rune) error {
+	if a == b {
+		return fmt.Errorf("unit %q cannot react with itself", a)
+	}
+
+	for _, unit := range []rune{a, b} {
+		if partner, prs := rules.partners[unit]; prs {
+			return fmt.Errorf("unit %q already reacts with %q", unit, partner)
+		}
+	}
+
+	rules.typeIndexes[a] = len(rules.types)
+	rules.typeIndexes[b] = len(rules.types)
+	rules.types = append(rules.types, [2]rune{a, b})
+	rules.partners[a] = b
+	rules.partners[b] = a
+
+	if a < 128 {
+		rules.asciiPartners[a] = b
+	}
+
+	if b < 128 {
+		rules.asciiPartners[b] = a
+	}
+
+	return nil
+}
+
+// reacts validates if unit a and unit b react
+// with each other.
+func (rules *ruleSet) reacts(a rune, b rune) bool {
+	if a >= 0 && a < 128 {
+		return rules.asciiPartners[a] == b
+	}
+
+	partner, prs := rules.partners[a]
+
+	return prs && partner == b
+}
+
+// typeName returns the name of this type, which
+// are both its units separated by a "/".
+func typeName(unitType [2]rune) string {
+	return string(unitType[0]) + "/" + string(unitType[1])
+}
+
+// parseRuleSet parses a ruleSet from this reader.
+//
+// Each line holds a reacting pair of two units,
+// separated by whitespace. Empty lines and lines
+// starting with "#" are ignored. For example:
+//
+// # Greek letters react with their upper case
+// Α α
+// Β β
+func parseRuleSet(reader io.Reader) (*ruleSet, error) {
+	rules := newRuleSet()
+
+	// Declare scanner to read from reader. Note that
+	// it skips blank lines, and each line is without
+	// surrounding whitespace and line ending.
+	scanner := parse.NewScanner(reader)
+
+	for scanner.Scan() {
+		line := scanner.Line()
+
+		if strings.HasPrefix(line.Text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line.Text)
+
+		if len(fields) != 2 || len([]rune(fields[0])) != 1 || len([]rune(fields[1])) != 1 {
+			return nil, line.Errorf("expected two units separated by whitespace, got %q", line.Text)
+		}
+
+		if err := rules.addPair([]rune(fields[0])[0], []rune(fields[1])[0]); err != nil {
+			return nil, line.Errorf("%v", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// loadRuleSet loads the ruleSet from the file with
+// this path. If path is empty, it returns the
+// ruleSet of the puzzle.
+//
+// Errors in the file start with its path, so they
+// are not mistaken for errors in the puzzle input.
+func loadRuleSet(path string) (*ruleSet, error) {
+	if path == "" {
+		return defaultRuleSet(), nil
+	}
+
+	// os.Open already includes path in its error
+	rulesFile, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rulesFile.Close()
+
+	rules, err := parseRuleSet(rulesFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// push pushes this unit on top of this stack of units
+// that survived so far, and returns the new stack.
+//
+// When unit reacts with the unit on top of the stack,
+// both are destroyed, so we pop the top of the stack
+// instead.
+func (rules *ruleSet) push(stack []rune, unit rune) []rune {
+	if len(stack) != 0 && rules.reacts(stack[len(stack)-1], unit) {
+		// unit and the top of the stack destroy
+		// each other, so pop the top of the
+		// stack and ignore unit.
+		return stack[:len(stack)-1]
+	}
+
+	return append(stack, unit)
+}
+
+// react fully reacts this polymer according to these
+// rules and returns the units that remain.
+//
+// Instead of repeatedly removing all reacting pairs
+// from the polymer until its length stops changing,
+// we process each unit exactly once. We keep the
+// units that survived so far on a stack. See push
+// for what happens with each unit. The stack then
+// holds the fully reacted polymer.
+//
+// This makes react linear in the length of polymer.
+func (rules *ruleSet) react(polymer []rune) []rune {
+	// In the worst case, no units react at all and
+	// the stack grows to the length of polymer.
+	stack := make([]rune, 0, len(polymer))
+
+	for _, unit := range polymer {
+		stack = rules.push(stack, unit)
+	}
+
+	return stack
+}
+
+// reactReader fully reacts the polymer that is read
+// from this reader according to these rules, and
+// returns the units that remain.
+//
+// Unlike react, reactReader does not need the entire
+// polymer in memory. It reads one unit at a time and
+// pushes it on the stack right away, so only the
+// units that survived so far are kept. Whitespace,
+// such as a trailing newline, is not a unit and is
+// ignored.
+//
+// If trace is not nil, each reaction is recorded
+// in trace. A polymer can be huge, so we stop
+// reading once ctx is done and return its error.
+func (rules *ruleSet) reactReader(ctx context.Context, reader io.Reader, trace *reactionTrace) ([]rune, error) {
+	// bufio.Reader reads large chunks from reader
+	// for us, while we read one rune at a time.
+	bufferedReader := bufio.NewReader(reader)
+
+	var stack []rune
+
+	// Keep track of the offset in bytes of each
+	// unit, so we can report where an invalid
+	// unit is.
+	offset := 0
+
+	// Number of runes we have read so far
+	runes := 0
+
+	for {
+		unit, size, err := bufferedReader.ReadRune()
+
+		if err == io.EOF {
+			return stack, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// ReadRune returns unicode.ReplacementChar
+		// with a size of 1 for invalid UTF-8.
+		if unit == unicode.ReplacementChar && size == 1 {
+			return nil, fmt.Errorf("invalid UTF-8 at byte offset %d", offset)
+		}
+
+		offset += size
+
+		// Checking ctx for each rune would make
+		// reacting noticeably slower.
+		runes++
+
+		if runes%(1<<16) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		if unicode.IsSpace(unit) {
+			continue
+		}
+
+		if trace != nil {
+			stack = trace.push(rules, stack, unit)
+		} else {
+			stack = rules.push(stack, unit)
+		}
+	}
+}
+
+// reactionEvent is a single reaction, in which two
+// adjacent units destroyed each other.
+type reactionEvent struct {
+	// Positions of the units in the original
+	// polymer, where the first unit is at
+	// position 0.
+	leftPosition  int
+	rightPosition int
+
+	leftUnit  rune
+	rightUnit rune
+}
+
+// reactionTrace records the reactions that happen
+// while fully reacting a polymer.
+//
+// Recording each reaction takes memory proportional
+// to the length of the polymer. So reactionEvents are
+// only kept for polymers up to replayLimit units, in
+// which case they can be replayed. For longer polymers,
+// only summary statistics are kept.
+type reactionTrace struct {
+	replayLimit int
+
+	// The original polymer and the reactions in the
+	// order they happened. Both are nil once the
+	// polymer is longer than replayLimit.
+	polymer []rune
+	events  []reactionEvent
+
+	// Positions of the units on the stack, so we
+	// know the positions of the units when they
+	// react.
+	positions []int
+
+	// Number of units we have read so far
+	units int
+
+	// Number of reactions per type, where the index
+	// is the index of the type in the ruleSet.
+	reactionsPerType []int
+
+	// The maximum number of units that were on
+	// the stack at the same time.
+	maxStackDepth int
+}
+
+// newReactionTrace creates a reactionTrace that keeps
+// its reactionEvents for polymers up to replayLimit
+// units.
+func newReactionTrace(replayLimit int) *reactionTrace {
+	return &reactionTrace{
+		replayLimit: replayLimit,
+		polymer:     []rune{},
+		events:      []reactionEvent{}}
+}
+
+// push pushes this unit on top of this stack like
+// rules.push, and records what happened.
+func (trace *reactionTrace) push(rules *ruleSet, stack []rune, unit rune) []rune {
+	position := trace.units
+
+	trace.units++
+
+	if trace.units > trace.replayLimit {
+		// This polymer is too long to replay,
+		// so stop keeping it and its events.
+		trace.polymer = nil
+		trace.events = nil
+	} else {
+		trace.polymer = append(trace.polymer, unit)
+	}
+
+	newStack := rules.push(stack, unit)
+
+	if len(newStack) > len(stack) {
+		// No reaction, unit is pushed
+		trace.positions = append(trace.positions, position)
+
+		if len(newStack) > trace.maxStackDepth {
+			trace.maxStackDepth = len(newStack)
+		}
+
+		return newStack
+	}
+
+	// unit reacted with the top of the stack, which
+	// is still in stack, because popping does not
+	// overwrite it.
+	event := reactionEvent{
+		leftPosition:  trace.positions[len(trace.positions)-1],
+		rightPosition: position,
+		leftUnit:      stack[len(stack)-1],
+		rightUnit:     unit}
+
+	trace.positions = trace.positions[:len(trace.positions)-1]
+
+	if trace.reactionsPerType == nil {
+		trace.reactionsPerType = make([]int, len(rules.types))
+	}
+
+	trace.reactionsPerType[rules.typeIndexes[unit]]++
+
+	if trace.events != nil {
+		trace.events = append(trace.events, event)
+	}
+
+	return newStack
+}
+
+// replay returns the sequence of intermediate polymers,
+// like the example in the README:
+//
+// dabAcCaCBAcCcaDA  'cC' at 4 and 5 is removed.
+// dabAaCBAcCcaDA    'Aa' at 3 and 6 is removed.
+// dabCBAcCcaDA      'cC' at 10 and 11 is removed.
+// dabCBAcaDA        No further actions can be taken.
+//
+// Each line shows the polymer before the reaction,
+// where the positions are in the original polymer.
+// It returns false if the polymer was too long to
+// replay.
+func (trace *reactionTrace) replay() ([]string, bool) {
+	if trace.events == nil {
+		return nil, false
+	}
+
+	// Keep track of which units of the original
+	// polymer are destroyed so far.
+	destroyed := make([]bool, len(trace.polymer))
+
+	// intermediatePolymer returns the original
+	// polymer without the destroyed units.
+	intermediatePolymer := func() string {
+		var builder strings.Builder
+
+		for i, unit := range trace.polymer {
+			if !destroyed[i] {
+				builder.WriteRune(unit)
+			}
+		}
+
+		return builder.String()
+	}
+
+	// Pad each polymer to the length of the original
+	// polymer, so all descriptions are aligned.
+	format := fmt.Sprintf("%%-%ds  %%s", len(trace.polymer))
+
+	var lines []string
+
+	for _, event := range trace.events {
+		description := fmt.Sprintf("'%c%c' at %d and %d is removed.",
+			event.leftUnit,
+			event.rightUnit,
+			event.leftPosition,
+			event.rightPosition)
+
+		lines = append(lines, fmt.Sprintf(format, intermediatePolymer(), description))
+
+		destroyed[event.leftPosition] = true
+		destroyed[event.rightPosition] = true
+	}
+
+	lines = append(lines, fmt.Sprintf(format, intermediatePolymer(), "No further actions can be taken."))
+
+	return lines, true
+}
+
+// summary returns summary statistics of this
+// trace, which can be used for polymers of
+// any length.
+func (trace *reactionTrace) summary(rules *ruleSet) []string {
+	// reactionsPerType is only created on the first
+	// reaction, so if nothing reacted, each type
+	// has zero reactions.
+	reactionsPerType := trace.reactionsPerType
+
+	if reactionsPerType == nil {
+		reactionsPerType = make([]int, len(rules.types))
+	}
+
+	reactions := 0
+
+	for _, count := range reactionsPerType {
+		reactions += count
+	}
+
+	lines := []string{
+		fmt.Sprintf("Units: %d", trace.units),
+		fmt.Sprintf("Reactions: %d", reactions),
+		fmt.Sprintf("Maximum stack depth: %d", trace.maxStackDepth)}
+
+	for i, count := range reactionsPerType {
+		lines = append(lines, fmt.Sprintf("Reactions of %s: %d", typeName(rules.types[i]), count))
+	}
+
+	return lines
+}
@@ -0,0 +1,203 @@
+package day05
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// generatePolymer generates a random polymer of
+// length units, of the types in these rules. We
+// use a fixed seed, so every run tests the
+// same polymer.
+func generatePolymer(rules *ruleSet, length int) []rune {
+	random := rand.New(rand.NewSource(5))
+
+	polymer := make([]rune, length)
+
+	for i := range polymer {
+		// Pick a random type and a random unit
+		// of that type.
+		polymer[i] = rules.types[random.Intn(len(rules.types))][random.Intn(2)]
+	}
+
+	return polymer
+}
+
+// reactByReplacing fully reacts this polymer the way
+// part_one.go used to do it: by removing all occurances
+// of "aA" and "Aa", for all characters of the alphabet,
+// until the length of polymer stops changing.
+func reactByReplacing(polymer string) string {
+	oldPolymerLength := len(polymer)
+	newPolymerLength := 0
+
+	for oldPolymerLength != newPolymerLength {
+		oldPolymerLength = len(polymer)
+
+		for upperChar := 'A'; upperChar <= 'Z'; upperChar++ {
+			lowerChar := upperChar | 32
+
+			polymer = strings.Replace(polymer, string(upperChar)+string(lowerChar), "", -1)
+			polymer = strings.Replace(polymer, string(lowerChar)+string(upperChar), "", -1)
+		}
+
+		newPolymerLength = len(polymer)
+	}
+
+	return polymer
+}
+
+// TestReact validates that react produces the same
+// fully reacted polymer as reactByReplacing, for the
+// example in the README and for generated polymers.
+func TestReact(t *testing.T) {
+	// reactByReplacing only knows the reacting
+	// pairs of the puzzle, so that is what we
+	// generate the polymers for.
+	rules := defaultRuleSet()
+
+	if got := string(rules.react([]rune("dabAcCaCBAcCcaDA"))); got != "dabCBAcaDA" {
+		t.Errorf("react of the README example is %q, expected %q", got, "dabCBAcaDA")
+	}
+
+	for _, length := range []int{0, 1, 2, 10, 1000, 100000} {
+		polymer := generatePolymer(rules, length)
+
+		if got, expected := string(rules.react(polymer)), reactByReplacing(string(polymer)); got != expected {
+			t.Errorf("react of a polymer of %d units has %d units, expected %d", length, len(got), len(expected))
+		}
+	}
+}
+
+// TestLoadRuleSet validates that errors in a rules
+// file start with its path.
+func TestLoadRuleSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+
+	if err := os.WriteFile(path, []byte("# Greek\nΑ α\nΑ β\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := loadRuleSet(path)
+
+	if expected := path + ": line 3: unit 'Α' already reacts with 'α'"; err == nil || err.Error() != expected {
+		t.Errorf("loadRuleSet returned error %v, expected %q", err, expected)
+	}
+}
+
+// TestReplay validates that a trace of the example in
+// the README replays each reaction.
+func TestReplay(t *testing.T) {
+	rules := defaultRuleSet()
+	trace := newReactionTrace(100)
+
+	if _, err := rules.reactReader(context.Background(), strings.NewReader("dabAcCaCBAcCcaDA\n"), trace); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, ok := trace.replay()
+
+	if !ok {
+		t.Fatal("the example is too long to replay")
+	}
+
+	expected := []string{
+		"dabAcCaCBAcCcaDA  'cC' at 4 and 5 is removed.",
+		"dabAaCBAcCcaDA    'Aa' at 3 and 6 is removed.",
+		"dabCBAcCcaDA      'cC' at 10 and 11 is removed.",
+		"dabCBAcaDA        No further actions can be taken.",
+	}
+
+	if !slices.Equal(lines, expected) {
+		t.Errorf("replay is\n%s\nexpected\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// TestSummary validates the summary of a trace that
+// is too long to replay, and of a trace in which
+// nothing reacted.
+func TestSummary(t *testing.T) {
+	rules := newRuleSet()
+
+	if err := rules.addPair('x', 'X'); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rules.addPair('y', 'Y'); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		polymer  string
+		expected []string
+	}{
+		{"xyYXxX", []string{"Units: 6", "Reactions: 3", "Maximum stack depth: 2", "Reactions of x/X: 2", "Reactions of y/Y: 1"}},
+		{"xy", []string{"Units: 2", "Reactions: 0", "Maximum stack depth: 2", "Reactions of x/X: 0", "Reactions of y/Y: 0"}},
+	}
+
+	for _, test := range tests {
+		// A replay limit of 1 is too short
+		// for each of these polymers.
+		trace := newReactionTrace(1)
+
+		if _, err := rules.reactReader(context.Background(), strings.NewReader(test.polymer), trace); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := trace.replay(); ok {
+			t.Errorf("%q can be replayed with a replay limit of 1", test.polymer)
+		}
+
+		if lines := trace.summary(rules); !slices.Equal(lines, test.expected) {
+			t.Errorf("summary of %q is %q, expected %q", test.polymer, lines, test.expected)
+		}
+	}
+}
+
+// BenchmarkReact fully reacts a generated polymer of
+// 1 million units with react.
+func BenchmarkReact(b *testing.B) {
+	rules := defaultRuleSet()
+
+	polymer := generatePolymer(rules, 1000000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rules.react(polymer)
+	}
+}
+
+// BenchmarkReactByReplacing fully reacts the same
+// polymer as BenchmarkReact with reactByReplacing,
+// to show how much faster react is.
+func BenchmarkReactByReplacing(b *testing.B) {
+	polymer := string(generatePolymer(defaultRuleSet(), 1000000))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reactByReplacing(polymer)
+	}
+}
+
+// TestReduceEachTypeCanceled validates that
+// reduceEachType stops once its context is done.
+func TestReduceEachTypeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rules := defaultRuleSet()
+
+	if _, _, err := reduceEachType(ctx, rules, []rune("dabAcCaCBAcCcaDA"), 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("reduceEachType returned error %v, expected %v", err, context.Canceled)
+	}
+}
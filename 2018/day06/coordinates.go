@@ -0,0 +1,443 @@
+// Package day06 solves the puzzle of day 6, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 6
+//
+// Part two accepts the flag -threshold, see
+// part_two.go. For example, for the example
+// in the README:
+//
+//	go run ./cmd/aoc run -input 2018/day06/example.txt 2018 6 2 -threshold 32
+package day06
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/TonnyGaric/adventofcode/internal/grid"
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+// coordinate is a location on the grid, where
+// 0,0 is at the top left.
+type coordinate = grid.Point
+
+// coordinatePattern matches a single coordinate, where
+// x and y are separated by a comma, for example:
+//
+//	300, 90
+var coordinatePattern = parse.MustCompile(`^(-?\d+),\s*(-?\d+)$`, "300, 90")
+
+// parseCoordinates parses all coordinates from this
+// reader, one coordinate per line.
+func parseCoordinates(reader io.Reader) ([]coordinate, error) {
+	coordinates, err := parse.Map(reader, func(line parse.Line) (coordinate, error) {
+		xy, err := coordinatePattern.Ints(line)
+
+		if err != nil {
+			return coordinate{}, err
+		}
+
+		return coordinate{X: xy[0], Y: xy[1]}, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(coordinates) == 0 {
+		return nil, fmt.Errorf("no coordinates found")
+	}
+
+	return coordinates, nil
+}
+
+// abs returns the absolute value of i
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// boundingBox returns the top left and bottom right
+// corner of the smallest box that contains all
+// these coordinates.
+func boundingBox(coordinates []coordinate) (coordinate, coordinate) {
+	topLeft := coordinates[0]
+	bottomRight := coordinates[0]
+
+	for _, c := range coordinates[1:] {
+		topLeft.X = min(topLeft.X, c.X)
+		topLeft.Y = min(topLeft.Y, c.Y)
+		bottomRight.X = max(bottomRight.X, c.X)
+		bottomRight.Y = max(bottomRight.Y, c.Y)
+	}
+
+	return topLeft, bottomRight
+}
+
+// closestCoordinate returns the index of the coordinate
+// that is closest to this location, or tie if two or
+// more coordinates are equally far from it.
+func closestCoordinate(coordinates []coordinate, location coordinate) int {
+	owner := tie
+	closestDistance := -1
+
+	for i, c := range coordinates {
+		distance := grid.ManhattanDistance(location, c)
+
+		if closestDistance == -1 || distance < closestDistance {
+			owner = i
+			closestDistance = distance
+		} else if distance == closestDistance {
+			owner = tie
+		}
+	}
+
+	return owner
+}
+
+// totalDistance calculates the sum of the distances
+// of this location to all coordinates.
+func totalDistance(coordinates []coordinate, location coordinate) int {
+	total := 0
+
+	for _, c := range coordinates {
+		total += grid.ManhattanDistance(location, c)
+	}
+
+	return total
+}
+
+// tie is the owner of a location that is equally
+// far from two or more coordinates.
+const tie = -1
+
+// maxWidth is the largest number of locations in a row
+// we keep in memory, both for the bounding box of the
+// areas and for the safe region.
+const maxWidth = 1 << 22
+
+// ownership determines, for each location within the
+// bounding box of the coordinates, which coordinate is
+// closest to it.
+//
+// It does so one row at a time, so it only keeps a
+// single row in memory, however tall the bounding box
+// is. Only the width of the bounding box and the
+// number of coordinates determine its memory.
+type ownership struct {
+	coordinates []coordinate
+
+	topLeft     coordinate
+	bottomRight coordinate
+
+	// Distance of each location of the current row
+	// to the coordinate that is closest to it.
+	distances []int
+}
+
+// newOwnership creates an ownership for the bounding box
+// of these coordinates. It returns an error if the box
+// is wider than maxWidth.
+func newOwnership(coordinates []coordinate) (*ownership, error) {
+	topLeft, bottomRight := boundingBox(coordinates)
+
+	width := bottomRight.X - topLeft.X + 1
+
+	// A width of 0 or less means that the
+	// subtraction overflowed.
+	if width <= 0 || width > maxWidth {
+		return nil, fmt.Errorf("the coordinates span from x %d to x %d, which is more than %d locations", topLeft.X, bottomRight.X, maxWidth)
+	}
+
+	return &ownership{
+		coordinates: coordinates,
+		topLeft:     topLeft,
+		bottomRight: bottomRight,
+		distances:   make([]int, width)}, nil
+}
+
+// width returns the number of locations in a
+// row of the bounding box.
+func (o *ownership) width() int {
+	return len(o.distances)
+}
+
+// row determines the owner of each location in row y,
+// from the left edge of the bounding box to the right
+// edge, and stores it in owners. Each owner is an index
+// in the coordinates, or tie.
+//
+// The distance of a location to a coordinate is the
+// distance along y, which is the same for the whole
+// row, plus the distance along x. So we first let each
+// coordinate claim the location in its own column, at
+// its distance along y. Then we sweep the row from left
+// to right, where each location can be claimed by the
+// owner of its left neighbor at one more distance, and
+// then from right to left likewise. After both sweeps,
+// each location is claimed by its closest coordinate.
+//
+// A location is tied if two different owners claim it
+// at the same distance, or if a tied location claims it.
+//
+// This makes row linear in the width of the bounding
+// box plus the number of coordinates.
+func (o *ownership) row(y int, owners []int) {
+	distances := o.distances
+
+	for x := range distances {
+		distances[x] = -1
+	}
+
+	// claim lets owner claim the location at x, if it
+	// is at least as close as the current owner.
+	claim := func(x int, distance int, owner int) {
+		switch {
+		case distances[x] == -1 || distance < distances[x]:
+			distances[x] = distance
+			owners[x] = owner
+		case distance == distances[x] && owner != owners[x]:
+			owners[x] = tie
+		}
+	}
+
+	for i, c := range o.coordinates {
+		claim(c.X-o.topLeft.X, abs(y-c.Y), i)
+	}
+
+	for x := 1; x < len(distances); x++ {
+		if distances[x-1] != -1 {
+			claim(x, distances[x-1]+1, owners[x-1])
+		}
+	}
+
+	for x := len(distances) - 2; x >= 0; x-- {
+		if distances[x+1] != -1 {
+			claim(x, distances[x+1]+1, owners[x+1])
+		}
+	}
+}
+
+// areas returns the size of the area of each coordinate
+// within the bounding box, and whether it is infinite.
+//
+// Outside the bounding box, moving further away from it
+// keeps the same coordinate closest. So a coordinate
+// that owns a location on the edge of the bounding box,
+// owns infinitely many locations beyond it.
+//
+// A tall bounding box has many rows, so we stop once
+// ctx is done and return its error.
+func (o *ownership) areas(ctx context.Context) ([]int, []bool, error) {
+	areas := make([]int, len(o.coordinates))
+	infinite := make([]bool, len(o.coordinates))
+
+	owners := make([]int, o.width())
+
+	for y := o.topLeft.Y; y <= o.bottomRight.Y; y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		o.row(y, owners)
+
+		onEdge := y == o.topLeft.Y || y == o.bottomRight.Y
+
+		for x, owner := range owners {
+			if owner == tie {
+				continue
+			}
+
+			areas[owner]++
+
+			if onEdge || x == 0 || x == len(owners)-1 {
+				infinite[owner] = true
+			}
+		}
+	}
+
+	return areas, infinite, nil
+}
+
+// largestFiniteArea returns the index of the coordinate
+// with the largest area that is not infinite, and the
+// size of that area. If all areas are infinite, the
+// index is -1.
+func (o *ownership) largestFiniteArea(ctx context.Context) (int, int, error) {
+	areas, infinite, err := o.areas(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	largest := -1
+	largestSize := 0
+
+	for i, size := range areas {
+		if !infinite[i] && size > largestSize {
+			largest = i
+			largestSize = size
+		}
+	}
+
+	return largest, largestSize, nil
+}
+
+// safeRegionSize calculates the number of locations of
+// which the total distance to all these coordinates is
+// less than threshold.
+//
+// The total distance of a location is the sum of the
+// distances along x, plus the sum of the distances
+// along y. Each sum is smallest at the median of its
+// values, and grows with each step away from it. So
+// the region only spans the x of which the sum along x,
+// plus the smallest sum along y, is less than threshold.
+// Likewise for y. See belowRange for how we find these
+// ranges without visiting each x or y.
+//
+// We then calculate both sums separately for each x and
+// each y in these ranges, so we never have to visit a
+// location to calculate its total distance. Then, for
+// each x, we count the number of y for which the sum of
+// both stays below threshold.
+func safeRegionSize(ctx context.Context, coordinates []coordinate, threshold int) (int, error) {
+	xs := make([]int, len(coordinates))
+	ys := make([]int, len(coordinates))
+
+	for i, c := range coordinates {
+		xs[i] = c.X
+		ys[i] = c.Y
+	}
+
+	sort.Ints(xs)
+	sort.Ints(ys)
+
+	xFirst, xLast, err := belowRange(xs, threshold-distanceSum(ys, ys[len(ys)/2]))
+
+	if err != nil {
+		return 0, err
+	}
+
+	yFirst, yLast, err := belowRange(ys, threshold-distanceSum(xs, xs[len(xs)/2]))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if xFirst > xLast || yFirst > yLast {
+		// Not even the median location
+		// is in the region.
+		return 0, nil
+	}
+
+	xDistances := distanceSums(xs, xFirst, xLast)
+	yDistances := distanceSums(ys, yFirst, yLast)
+
+	// Sort the sums along y, so the number of y that
+	// fit together with an x is a prefix of them.
+	sort.Ints(yDistances)
+
+	size := 0
+
+	for i, xDistance := range xDistances {
+		if i%(1<<16) == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		// Number of y of which the sum is less
+		// than threshold - xDistance.
+		size += sort.SearchInts(yDistances, threshold-xDistance)
+	}
+
+	return size, nil
+}
+
+// distanceSum calculates the sum of the distances
+// of this position to all these values.
+func distanceSum(values []int, position int) int {
+	sum := 0
+
+	for _, value := range values {
+		sum += abs(position - value)
+	}
+
+	return sum
+}
+
+// belowRange returns the first and last position of
+// which the sum of its distances to these sorted values
+// is less than bound. If there is no such position,
+// first is larger than last. It returns an error if
+// the range is wider than maxWidth.
+//
+// The sum is smallest at the median, and grows by at
+// least 1 with each step away from it. So we search
+// for the first step away from the median on each
+// side, where the sum is no longer less than bound.
+//
+// See: https://golang.org/pkg/sort/#Search
+func belowRange(sorted []int, bound int) (int, int, error) {
+	median := sorted[len(sorted)/2]
+
+	if distanceSum(sorted, median) >= bound {
+		return 0, -1, nil
+	}
+
+	reach := maxWidth / 2
+
+	if distanceSum(sorted, median-reach) < bound || distanceSum(sorted, median+reach) < bound {
+		return 0, 0, fmt.Errorf("the safe region is more than %d locations wide", maxWidth)
+	}
+
+	left := sort.Search(reach, func(step int) bool {
+		return distanceSum(sorted, median-step) >= bound
+	})
+
+	right := sort.Search(reach, func(step int) bool {
+		return distanceSum(sorted, median+step) >= bound
+	})
+
+	return median - left + 1, median + right - 1, nil
+}
+
+// distanceSums calculates, for each value from first up
+// to and including last, the sum of its distances to
+// all these sorted values.
+//
+// Moving one step to the right, brings us one step closer
+// to each value on our right, and one step further away
+// from each value on our left or at our position. With the
+// values sorted, we can keep track of how many are on our
+// left while we move.
+func distanceSums(sorted []int, first int, last int) []int {
+	sums := make([]int, 0, last-first+1)
+
+	// Sum of distances at first
+	sum := distanceSum(sorted, first)
+
+	// Number of values at or left of
+	// the current position.
+	left := 0
+
+	for position := first; position <= last; position++ {
+		for left < len(sorted) && sorted[left] <= position {
+			left++
+		}
+
+		sums = append(sums, sum)
+
+		sum += left - (len(sorted) - left)
+	}
+
+	return sums
+}
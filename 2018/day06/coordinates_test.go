@@ -0,0 +1,288 @@
+package day06
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/grid"
+)
+
+// example is the list of coordinates of the
+// example in the README.
+const example = `1, 1
+1, 6
+8, 3
+3, 4
+5, 5
+8, 9
+`
+
+// TestExample validates both parts against the
+// example in the README.
+func TestExample(t *testing.T) {
+	tests := []struct {
+		name     string
+		solve    aoc.Solver
+		args     []string
+		expected string
+	}{
+		{"part one", partOne, nil, "17"},
+		{"part two", partTwo, []string{"-threshold", "32"}, "16"},
+	}
+
+	for _, test := range tests {
+		answer, err := test.solve(context.Background(), strings.NewReader(example), test.args, io.Discard)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if answer != test.expected {
+			t.Errorf("%s: answer is %s, expected %s", test.name, answer, test.expected)
+		}
+	}
+}
+
+// TestNegativeThreshold validates that part two
+// rejects a negative threshold.
+func TestNegativeThreshold(t *testing.T) {
+	if _, err := partTwo(context.Background(), strings.NewReader(example), []string{"-threshold", "-100"}, io.Discard); err == nil {
+		t.Error("part two accepted a threshold of -100")
+	}
+}
+
+// TestLargeBoundingBox validates that part one handles
+// a tall bounding box without keeping it in memory, and
+// rejects a bounding box that is too wide.
+func TestLargeBoundingBox(t *testing.T) {
+	tall := "0, 0\n2, 1000000\n1, 3\n"
+
+	if _, err := partOne(context.Background(), strings.NewReader(tall), nil, io.Discard); err == nil || err.Error() != "all areas are infinite" {
+		t.Errorf("part one returned error %v for a tall bounding box, expected that all areas are infinite", err)
+	}
+
+	wide := fmt.Sprintf("0, 0\n%d, 3\n", maxWidth)
+
+	if _, err := partOne(context.Background(), strings.NewReader(wide), nil, io.Discard); err == nil {
+		t.Errorf("part one accepted a bounding box %d locations wide", maxWidth+1)
+	}
+}
+
+// TestLargeThreshold validates the size of the safe
+// region around a single coordinate, and that part two
+// rejects a threshold that makes it too wide.
+func TestLargeThreshold(t *testing.T) {
+	// Around a single coordinate, the region is a
+	// diamond of all locations with a distance of
+	// up to 999, which has 2 * 999 * 1000 + 1
+	// locations.
+	answer, err := partTwo(context.Background(), strings.NewReader("0, 0\n"), []string{"-threshold", "1000"}, io.Discard)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if answer != "1998001" {
+		t.Errorf("answer is %s, expected 1998001", answer)
+	}
+
+	if _, err := partTwo(context.Background(), strings.NewReader(example), []string{"-threshold", "100000000000000"}, io.Discard); err == nil {
+		t.Error("part two accepted a threshold of 100000000000000")
+	}
+}
+
+// bruteForceOwnership determines the owner of each
+// location in the bounding box, by calculating its
+// distance to each coordinate.
+func bruteForceOwnership(coordinates []coordinate) (*grid.Grid[int], error) {
+	topLeft, bottomRight := boundingBox(coordinates)
+
+	return closestCoordinates(context.Background(), coordinates, topLeft, bottomRight)
+}
+
+// bruteForceAreas returns the size of the area of each
+// coordinate in owners, and whether it is infinite,
+// because it reaches the edge of owners.
+func bruteForceAreas(coordinates []coordinate, owners *grid.Grid[int]) ([]int, []bool) {
+	areas := make([]int, len(coordinates))
+	infinite := make([]bool, len(coordinates))
+
+	topLeft, bottomRight := owners.Bounds()
+
+	for location, owner := range owners.All() {
+		if owner == tie {
+			continue
+		}
+
+		areas[owner]++
+
+		if location.X == topLeft.X || location.X == bottomRight.X || location.Y == topLeft.Y || location.Y == bottomRight.Y {
+			infinite[owner] = true
+		}
+	}
+
+	return areas, infinite
+}
+
+// bruteForceSafeRegionSize calculates the size of the
+// safe region, by calculating the total distance of
+// each location that could be in it.
+func bruteForceSafeRegionSize(coordinates []coordinate, threshold int) int {
+	topLeft, bottomRight := boundingBox(coordinates)
+
+	margin := threshold / len(coordinates)
+
+	size := 0
+
+	for y := topLeft.Y - margin; y <= bottomRight.Y+margin; y++ {
+		for x := topLeft.X - margin; x <= bottomRight.X+margin; x++ {
+			if totalDistance(coordinates, coordinate{X: x, Y: y}) < threshold {
+				size++
+			}
+		}
+	}
+
+	return size
+}
+
+// generateCoordinates generates n random coordinates,
+// with x and y from 0 up to but not including size.
+func generateCoordinates(random *rand.Rand, n int, size int) []coordinate {
+	coordinates := make([]coordinate, n)
+
+	for i := range coordinates {
+		coordinates[i] = coordinate{X: random.Intn(size), Y: random.Intn(size)}
+	}
+
+	return coordinates
+}
+
+// compare validates that the engine and the brute
+// force scan agree on these coordinates.
+func compare(coordinates []coordinate, threshold int) error {
+	ctx := context.Background()
+
+	fast, err := newOwnership(coordinates)
+
+	if err != nil {
+		return err
+	}
+
+	slow, err := bruteForceOwnership(coordinates)
+
+	if err != nil {
+		return err
+	}
+
+	topLeft, bottomRight := slow.Bounds()
+
+	owners := make([]int, fast.width())
+
+	for y := topLeft.Y; y <= bottomRight.Y; y++ {
+		fast.row(y, owners)
+
+		for x, owner := range owners {
+			location := coordinate{X: topLeft.X + x, Y: y}
+
+			if expected := slow.Get(location); owner != expected {
+				return fmt.Errorf("owner of location %d, %d is %d, expected %d", location.X, location.Y, owner, expected)
+			}
+		}
+	}
+
+	fastAreas, fastInfinite, err := fast.areas(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	slowAreas, slowInfinite := bruteForceAreas(coordinates, slow)
+
+	for i := range coordinates {
+		if fastAreas[i] != slowAreas[i] || fastInfinite[i] != slowInfinite[i] {
+			return fmt.Errorf("area of coordinate %d is %d (infinite: %t), expected %d (infinite: %t)", i, fastAreas[i], fastInfinite[i], slowAreas[i], slowInfinite[i])
+		}
+	}
+
+	fastSize, err := safeRegionSize(ctx, coordinates, threshold)
+
+	if err != nil {
+		return err
+	}
+
+	if slowSize := bruteForceSafeRegionSize(coordinates, threshold); fastSize != slowSize {
+		return fmt.Errorf("safe region size with threshold %d is %d, expected %d", threshold, fastSize, slowSize)
+	}
+
+	return nil
+}
+
+// TestCompareWithBruteForce validates that the engine
+// and the brute force scan agree on many small random
+// inputs.
+func TestCompareWithBruteForce(t *testing.T) {
+	// Use a fixed seed, so every run
+	// checks the same inputs.
+	random := rand.New(rand.NewSource(6))
+
+	for i := 0; i < 1000; i++ {
+		// Small grids with many coordinates
+		// produce a lot of ties.
+		coordinates := generateCoordinates(random, 1+random.Intn(20), 1+random.Intn(30))
+
+		threshold := random.Intn(500)
+
+		if err := compare(coordinates, threshold); err != nil {
+			t.Fatalf("%v for coordinates %v", err, coordinates)
+		}
+	}
+}
+
+// largeCoordinates are 1000 random coordinates of up
+// to 1000, 1000, to benchmark the engine and the
+// brute force scan on.
+func largeCoordinates() []coordinate {
+	return generateCoordinates(rand.New(rand.NewSource(6)), 1000, 1000)
+}
+
+// BenchmarkEngine solves both parts for
+// largeCoordinates with the engine.
+func BenchmarkEngine(b *testing.B) {
+	coordinates := largeCoordinates()
+
+	for i := 0; i < b.N; i++ {
+		ownership, err := newOwnership(coordinates)
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if _, _, err := ownership.largestFiniteArea(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+
+		if _, err := safeRegionSize(context.Background(), coordinates, 100000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBruteForce solves both parts for
+// largeCoordinates with the brute force scan.
+func BenchmarkBruteForce(b *testing.B) {
+	coordinates := largeCoordinates()
+
+	for i := 0; i < b.N; i++ {
+		owners, err := bruteForceOwnership(coordinates)
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		bruteForceAreas(coordinates, owners)
+		bruteForceSafeRegionSize(coordinates, 100000)
+	}
+}
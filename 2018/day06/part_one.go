@@ -0,0 +1,54 @@
+package day06
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+func init() {
+	aoc.Register(2018, 6, 1, partOne)
+}
+
+// partOne finds the size of the largest area around
+// the coordinates in input, that is not infinite.
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
+	}
+
+	coordinates, err := parseCoordinates(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Determine for each location which coordinate is
+	// closest. See coordinates.go for how this is done.
+	ownership, err := newOwnership(coordinates)
+
+	if err != nil {
+		return "", err
+	}
+
+	// The size of the largest area that is not
+	// infinite will be our final answer.
+	largest, answer, err := ownership.largestFiniteArea(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	if largest == -1 {
+		return "", errors.New("all areas are infinite")
+	}
+
+	fmt.Fprintf(output, "%d is the size of the largest area that isn't infinite, around coordinate %d, %d.\n", answer, coordinates[largest].X, coordinates[largest].Y)
+
+	// The final answer
+	return strconv.Itoa(answer), nil
+}
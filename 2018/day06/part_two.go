@@ -0,0 +1,62 @@
+package day06
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+func init() {
+	aoc.Register(2018, 6, 2, partTwo)
+}
+
+// partTwo finds the size of the region of locations
+// of which the total distance to all coordinates in
+// input is less than a threshold.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 6 part 2", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// According to the puzzle, the total distance of a
+	// location to all coordinates must be less than
+	// 10000. The example uses 32, so we make this
+	// configurable.
+	threshold := flags.Int("threshold", 10000, "total distance to all coordinates a location must stay below")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	// No total distance is less than a negative
+	// threshold, so that is surely a mistake.
+	if *threshold < 0 {
+		return "", fmt.Errorf("threshold must not be negative, got %d", *threshold)
+	}
+
+	coordinates, err := parseCoordinates(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	// This will be our final answer. See coordinates.go
+	// for how this is calculated.
+	answer, err := safeRegionSize(ctx, coordinates, *threshold)
+
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintln(output, answer, "is the size of the region containing all locations which have a total distance to all given coordinates of less than", *threshold)
+
+	// The final answer
+	return strconv.Itoa(answer), nil
+}
@@ -0,0 +1,268 @@
+// render.go draws which coordinate is closest to each
+// location, so we can visually verify which areas are
+// infinite. Run it with:
+//
+//	go run ./cmd/aoc tool 2018 6 render
+//
+// This prints a map like the one in the README. With
+// -png, it also draws the map as an image.
+
+package day06
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"unicode"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+	"github.com/TonnyGaric/adventofcode/internal/grid"
+)
+
+// letter returns the letter of the coordinate with this
+// index, like in the README: "a" for the first, "b" for
+// the second, and so on. There are only 26 letters, so
+// after "z" we start over at "a".
+func letter(index int) rune {
+	return rune('a' + index%26)
+}
+
+// closestCoordinates determines, for each location from
+// topLeft up to and including bottomRight, the index of
+// the coordinate that is closest to it, or tie.
+//
+// Unlike ownership, this is not limited to the bounding
+// box of the coordinates, but it keeps every location
+// in memory. A large map takes a while, so we stop once
+// ctx is done and return its error.
+func closestCoordinates(ctx context.Context, coordinates []coordinate, topLeft coordinate, bottomRight coordinate) (*grid.Grid[int], error) {
+	owners := grid.New[int](topLeft, bottomRight)
+
+	for y := topLeft.Y; y <= bottomRight.Y; y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for x := topLeft.X; x <= bottomRight.X; x++ {
+			location := coordinate{X: x, Y: y}
+
+			owners.Set(location, closestCoordinate(coordinates, location))
+		}
+	}
+
+	return owners, nil
+}
+
+// renderText draws these owners like the map in the
+// README:
+//
+//	aaaaa.cccc
+//	aAaaa.cccc
+//	aaaddecccc
+//
+// Each location shows the lower case letter of the
+// coordinate closest to it, or a "." if it is tied.
+// Each coordinate itself is shown in upper case.
+func renderText(coordinates []coordinate, owners *grid.Grid[int]) string {
+	return owners.Text(func(location coordinate, owner int) rune {
+		switch {
+		case owner == tie:
+			return '.'
+		case coordinates[owner] == location:
+			return unicode.ToUpper(letter(owner))
+		default:
+			return letter(owner)
+		}
+	})
+}
+
+// palette returns a distinct color for each of these
+// coordinates, by spreading their hues evenly around
+// the color wheel.
+//
+// See: https://en.wikipedia.org/wiki/HSL_and_HSV#HSV_to_RGB
+func palette(coordinates []coordinate) []color.RGBA {
+	colors := make([]color.RGBA, len(coordinates))
+
+	for i := range coordinates {
+		hue := float64(i) / float64(len(coordinates)) * 6
+
+		// Value and saturation are fixed, so only the
+		// hue determines the color.
+		chroma := 0.8 * 0.9
+		secondary := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+		offset := 0.9 - chroma
+
+		var r, g, b float64
+
+		switch int(hue) {
+		case 0:
+			r, g, b = chroma, secondary, 0
+		case 1:
+			r, g, b = secondary, chroma, 0
+		case 2:
+			r, g, b = 0, chroma, secondary
+		case 3:
+			r, g, b = 0, secondary, chroma
+		case 4:
+			r, g, b = secondary, 0, chroma
+		default:
+			r, g, b = chroma, 0, secondary
+		}
+
+		colors[i] = color.RGBA{
+			R: uint8((r + offset) * 255),
+			G: uint8((g + offset) * 255),
+			B: uint8((b + offset) * 255),
+			A: 255}
+	}
+
+	return colors
+}
+
+// blend mixes color c with other, where weight is the
+// share of other in the result.
+func blend(c color.RGBA, other color.RGBA, weight float64) color.RGBA {
+	mix := func(a uint8, b uint8) uint8 {
+		return uint8(float64(a)*(1-weight) + float64(b)*weight)
+	}
+
+	return color.RGBA{R: mix(c.R, other.R), G: mix(c.G, other.G), B: mix(c.B, other.B), A: 255}
+}
+
+// locationColor returns the color of each location
+// when drawing owners as an image.
+//
+// Each location gets the color of the coordinate closest
+// to it, or gray if it is tied. Locations of which the
+// closest coordinate has an infinite area are dimmed.
+// Locations with a total distance to all coordinates of
+// less than threshold, are brightened to show the safe
+// region. The coordinates themselves are black.
+func locationColor(coordinates []coordinate, infinite []bool, threshold int) func(location coordinate, owner int) color.Color {
+	colors := palette(coordinates)
+
+	gray := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	black := color.RGBA{A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	return func(location coordinate, owner int) color.Color {
+		var c color.RGBA
+
+		switch {
+		case owner == tie:
+			c = gray
+		case coordinates[owner] == location:
+			c = black
+		case infinite[owner]:
+			c = blend(colors[owner], black, 0.6)
+		default:
+			c = colors[owner]
+		}
+
+		if c != black && totalDistance(coordinates, location) < threshold {
+			c = blend(c, white, 0.5)
+		}
+
+		return c
+	}
+}
+
+func init() {
+	aoc.RegisterTool(2018, 6, "render", renderTool)
+}
+
+// renderTool prints which coordinate is closest to each
+// location, and optionally draws it as a PNG image.
+func renderTool(ctx context.Context, input io.Reader, args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("2018 day 6 tool render", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// Number of locations to draw beyond the bounding
+	// box of the coordinates on each side.
+	margin := flags.Int("margin", 1, "number of locations to draw around the coordinates")
+
+	// When set, also draw the map as a PNG image to
+	// this path.
+	pngPath := flags.String("png", "", "path of the PNG image to draw")
+
+	scale := flags.Int("scale", 2, "width and height in pixels of each location in the PNG image")
+
+	// See part_two.go
+	threshold := flags.Int("threshold", 10000, "total distance to all coordinates a location must stay below to be safe")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return err
+	}
+
+	// Validate the flags before we read anything,
+	// so a bad flag never leaves an empty image
+	// behind.
+	if *scale < 1 {
+		return fmt.Errorf("scale must be at least 1, got %d", *scale)
+	}
+
+	if *margin < 0 {
+		return fmt.Errorf("margin must not be negative, got %d", *margin)
+	}
+
+	coordinates, err := parseCoordinates(input)
+
+	if err != nil {
+		return err
+	}
+
+	topLeft, bottomRight := boundingBox(coordinates)
+
+	topLeft = coordinate{X: topLeft.X - *margin, Y: topLeft.Y - *margin}
+	bottomRight = coordinate{X: bottomRight.X + *margin, Y: bottomRight.Y + *margin}
+
+	owners, err := closestCoordinates(ctx, coordinates, topLeft, bottomRight)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(output, renderText(coordinates, owners))
+
+	if *pngPath == "" {
+		return nil
+	}
+
+	ownership, err := newOwnership(coordinates)
+
+	if err != nil {
+		return err
+	}
+
+	_, infinite, err := ownership.areas(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	pngFile, err := os.Create(*pngPath)
+
+	if err != nil {
+		return err
+	}
+
+	if err := owners.EncodePNG(pngFile, *scale, locationColor(coordinates, infinite, *threshold)); err != nil {
+		pngFile.Close()
+
+		return err
+	}
+
+	// Closing the file can fail to write what
+	// is left of the image, so we do not
+	// ignore its error.
+	return pngFile.Close()
+}
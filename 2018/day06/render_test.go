@@ -0,0 +1,67 @@
+package day06
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// TestRenderText validates that renderText draws the
+// map of the example in the README.
+func TestRenderText(t *testing.T) {
+	coordinates, err := parseCoordinates(strings.NewReader(example))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `aaaaa.cccc
+aAaaa.cccc
+aaaddecccc
+aadddeccCc
+..dDdeeccc
+bb.deEeecc
+bBb.eeee..
+bbb.eeefff
+bbb.eeffff
+bbb.ffffFf
+`
+
+	owners, err := closestCoordinates(context.Background(), coordinates, coordinate{X: 0, Y: 0}, coordinate{X: 9, Y: 9})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := renderText(coordinates, owners); got != expected {
+		t.Errorf("renderText drew:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+// TestCanceled validates that both parts and the
+// render tool stop once their context is done.
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name  string
+		solve aoc.Solver
+	}{
+		{"part one", partOne},
+		{"part two", partTwo},
+		{"render", func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+			return "", renderTool(ctx, input, args, output)
+		}},
+	}
+
+	for _, test := range tests {
+		if _, err := test.solve(ctx, strings.NewReader(example), nil, io.Discard); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s returned error %v, expected %v", test.name, err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// analyze.go exports the steps in the puzzle input as a
+// graph and explains how long they take to complete.
+// Run it with:
+//
+//	go run ./cmd/aoc tool 2018 7 analyze
+//
+// With -format dot or -format mermaid, it prints the
+// graph in that format instead.
+
+package day07
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+func init() {
+	aoc.RegisterTool(2018, 7, "analyze", analyzeTool)
+}
+
+// analyzeTool prints the steps as a graph, or explains
+// how long they take to complete.
+func analyzeTool(ctx context.Context, input io.Reader, args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("2018 day 7 tool analyze", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	format := flags.String("format", "", "print the graph as \"dot\" or \"mermaid\"")
+
+	// See part_two.go
+	base := flags.Int("base", 60, "number of seconds each step takes before adding its letter")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return err
+	}
+
+	// Like in part two, steps cannot take a
+	// negative number of seconds.
+	if *base < 0 {
+		return fmt.Errorf("base must not be negative, got %d", *base)
+	}
+
+	// See steps.go for how the steps are parsed
+	steps, err := parseSteps(input)
+
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "dot":
+		fmt.Fprint(output, steps.dot())
+
+		return nil
+	case "mermaid":
+		fmt.Fprint(output, steps.mermaid())
+
+		return nil
+	case "":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	// See graph.go for how the critical
+	// path is determined.
+	timings, totalTime, path, err := steps.criticalPath(stepDuration(*base))
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(output, "Step  Earliest start  Latest start  Slack")
+
+	for _, name := range steps.nodes() {
+		t := timings[name]
+
+		fmt.Fprintf(output, "%-4s  %14d  %12d  %5d\n", name, t.earliestStart, t.latestStart, t.slack())
+	}
+
+	fmt.Fprintln(output, "The critical path is", strings.Join(path, " -> "))
+	fmt.Fprintln(output, "With unlimited workers, it would take", totalTime, "seconds to complete all of the steps.")
+
+	return nil
+}
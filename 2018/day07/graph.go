@@ -0,0 +1,457 @@
+// This file holds the graph of steps, which is used
+// by both parts. See steps.go for how to run either
+// part.
+
+package day07
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graph is a directed graph of steps, where each edge
+// goes from a prerequisite to a step that depends on it.
+type graph struct {
+	// Map where:
+	// - key: name of step
+	// - value: set of names of the steps that
+	//          depend on key
+	dependents map[string]map[string]struct{}
+
+	// Map where:
+	// - key: name of step
+	// - value: set of names of the prerequisites
+	//          of key
+	prerequisites map[string]map[string]struct{}
+}
+
+// newGraph creates a graph without any steps
+func newGraph() *graph {
+	return &graph{
+		dependents:    make(map[string]map[string]struct{}),
+		prerequisites: make(map[string]map[string]struct{})}
+}
+
+// addNode adds the step with this name to this
+// graph, if it is not already present.
+func (g *graph) addNode(name string) {
+	if _, prs := g.dependents[name]; !prs {
+		g.dependents[name] = make(map[string]struct{})
+		g.prerequisites[name] = make(map[string]struct{})
+	}
+}
+
+// addEdge adds that step prerequisite must be finished
+// before step can begin. Both steps are added to this
+// graph, if they are not already present.
+func (g *graph) addEdge(prerequisite string, step string) {
+	g.addNode(prerequisite)
+	g.addNode(step)
+
+	g.dependents[prerequisite][step] = struct{}{}
+	g.prerequisites[step][prerequisite] = struct{}{}
+}
+
+// nodes returns the names of all steps in
+// this graph, in alphabetical order.
+func (g *graph) nodes() []string {
+	names := make([]string, 0, len(g.dependents))
+
+	for name := range g.dependents {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// inDegrees returns a map where:
+// - key: name of step
+// - value: number of prerequisites of key
+func (g *graph) inDegrees() map[string]int {
+	inDegrees := make(map[string]int)
+
+	for name, prerequisites := range g.prerequisites {
+		inDegrees[name] = len(prerequisites)
+	}
+
+	return inDegrees
+}
+
+// topologicalOrder returns the order in which the steps
+// should be completed. If more than one step is ready,
+// the step which is first alphabetically is chosen.
+//
+// We use Kahn's algorithm: we keep track of the number
+// of prerequisites of each step that are not completed
+// yet. Steps without any are ready and kept in a heap,
+// so we can always take the first alphabetically
+// without sorting all ready steps again. Completing
+// a step may make the steps that depend on it ready.
+//
+// If the steps depend on each other in a cycle, they
+// can never be completed and a *cycleError is
+// returned.
+//
+// See: https://en.wikipedia.org/wiki/Topological_sorting#Kahn's_algorithm
+func (g *graph) topologicalOrder() ([]string, error) {
+	inDegrees := g.inDegrees()
+
+	ready := &stepHeap{}
+
+	for name, inDegree := range inDegrees {
+		if inDegree == 0 {
+			heap.Push(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(inDegrees))
+
+	for ready.Len() != 0 {
+		name := heap.Pop(ready).(string)
+
+		order = append(order, name)
+
+		for dependent := range g.dependents[name] {
+			inDegrees[dependent]--
+
+			if inDegrees[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(inDegrees) {
+		return nil, g.findCycle(inDegrees)
+	}
+
+	return order, nil
+}
+
+// findCycle finds a cycle among the steps that could not
+// be completed, which are the steps in inDegrees that
+// still have prerequisites left.
+//
+// Each of these steps has at least one prerequisite that
+// could not be completed either. So when we keep walking
+// from a step to such a prerequisite, we must eventually
+// visit a step for the second time. The steps from its
+// first visit up to the second form a cycle.
+func (g *graph) findCycle(inDegrees map[string]int) *cycleError {
+	var blocked []string
+
+	for name, inDegree := range inDegrees {
+		if inDegree > 0 {
+			blocked = append(blocked, name)
+		}
+	}
+
+	// Start at the first blocked step alphabetically,
+	// so we always report the same cycle.
+	sort.Strings(blocked)
+
+	// Make a map where:
+	// - key: name of visited step
+	// - value: index of key in path
+	visited := make(map[string]int)
+
+	var path []string
+
+	for name := blocked[0]; ; {
+		if index, prs := visited[name]; prs {
+			cycle := path[index:]
+
+			// We walked from steps to their prerequisites,
+			// reverse the cycle so it follows the order in
+			// which the steps would be completed.
+			for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+				cycle[i], cycle[j] = cycle[j], cycle[i]
+			}
+
+			// Let the cycle start at its first step
+			// alphabetically.
+			first := 0
+
+			for i, step := range cycle {
+				if step < cycle[first] {
+					first = i
+				}
+			}
+
+			return &cycleError{steps: append(cycle[first:], cycle[:first]...)}
+		}
+
+		visited[name] = len(path)
+		path = append(path, name)
+
+		// Walk to the first blocked prerequisite
+		// alphabetically.
+		var next string
+
+		for prerequisite := range g.prerequisites[name] {
+			if inDegrees[prerequisite] > 0 && (next == "" || prerequisite < next) {
+				next = prerequisite
+			}
+		}
+
+		name = next
+	}
+}
+
+// cycleError is returned when steps depend on
+// each other in a cycle.
+type cycleError struct {
+	// Names of the steps in the cycle, where each
+	// step is a prerequisite of the next one, and
+	// the last one is a prerequisite of the first.
+	steps []string
+}
+
+func (e *cycleError) Error() string {
+	return fmt.Sprintf("steps depend on each other in a cycle: %s -> %s", strings.Join(e.steps, " -> "), e.steps[0])
+}
+
+// stepHeap is a min-heap of names of steps, so the
+// first step alphabetically is always on top.
+//
+// See: https://golang.org/pkg/container/heap/
+type stepHeap []string
+
+func (h stepHeap) Len() int           { return len(h) }
+func (h stepHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h stepHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *stepHeap) Push(x interface{}) {
+	*h = append(*h, x.(string))
+}
+
+func (h *stepHeap) Pop() interface{} {
+	old := *h
+	name := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return name
+}
+
+// edges returns all edges of this graph as pairs of
+// prerequisite and step, in alphabetical order.
+func (g *graph) edges() [][2]string {
+	var edges [][2]string
+
+	for _, prerequisite := range g.nodes() {
+		var steps []string
+
+		for step := range g.dependents[prerequisite] {
+			steps = append(steps, step)
+		}
+
+		sort.Strings(steps)
+
+		for _, step := range steps {
+			edges = append(edges, [2]string{prerequisite, step})
+		}
+	}
+
+	return edges
+}
+
+// dot returns this graph in the DOT language of
+// Graphviz, for example:
+//
+//	digraph steps {
+//	    "C" -> "A";
+//	    "C" -> "F";
+//	}
+//
+// See: https://graphviz.org/doc/info/lang.html
+func (g *graph) dot() string {
+	var builder strings.Builder
+
+	builder.WriteString("digraph steps {\n")
+
+	// Steps without any edge would not show up
+	// otherwise, so we list all steps first.
+	for _, name := range g.nodes() {
+		fmt.Fprintf(&builder, "    %q;\n", name)
+	}
+
+	for _, edge := range g.edges() {
+		fmt.Fprintf(&builder, "    %q -> %q;\n", edge[0], edge[1])
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// mermaid returns this graph as a Mermaid flowchart,
+// for example:
+//
+//	graph LR
+//	    step0["A"]
+//	    step1["C"]
+//	    step1 --> step0
+//
+// Mermaid does not allow all characters in the ID of
+// a node, so each step gets an ID based on its index
+// and its name as label. See mermaidLabel for how
+// the name is escaped.
+//
+// See: https://mermaid.js.org/syntax/flowchart.html
+func (g *graph) mermaid() string {
+	var builder strings.Builder
+
+	builder.WriteString("graph LR\n")
+
+	ids := make(map[string]string)
+
+	for i, name := range g.nodes() {
+		ids[name] = fmt.Sprintf("step%d", i)
+
+		fmt.Fprintf(&builder, "    %s[%s]\n", ids[name], mermaidLabel(name))
+	}
+
+	for _, edge := range g.edges() {
+		fmt.Fprintf(&builder, "    %s --> %s\n", ids[edge[0]], ids[edge[1]])
+	}
+
+	return builder.String()
+}
+
+// mermaidEscaper replaces the characters Mermaid would
+// otherwise interpret in a label by their entity codes.
+// The "#" is escaped as well, so a name that looks like
+// an entity code is shown as it is.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#60;",
+	">", "#62;")
+
+// mermaidLabel returns this name as the quoted label
+// of a node in a Mermaid flowchart.
+//
+// Mermaid does not understand the backslash escapes of
+// Go, so instead we escape characters by their entity
+// codes, for example "#quot;" for a double quote.
+//
+// See: https://mermaid.js.org/syntax/flowchart.html#entity-codes-to-escape-characters
+func mermaidLabel(name string) string {
+	return `"` + mermaidEscaper.Replace(name) + `"`
+}
+
+// timing is when a step can be done, if there
+// are enough workers to never wait for one.
+type timing struct {
+	// The earliest second at which the step can
+	// begin and end, when all its prerequisites
+	// end as early as possible.
+	earliestStart int
+	earliestEnd   int
+
+	// The latest second at which the step can
+	// begin and end, without delaying the
+	// completion of all steps.
+	latestStart int
+	latestEnd   int
+}
+
+// slack returns the number of seconds the step can
+// be delayed without delaying the completion of
+// all steps.
+func (t timing) slack() int {
+	return t.latestStart - t.earliestStart
+}
+
+// criticalPath analyses this graph where each step takes
+// duration seconds, with unlimited workers.
+//
+// It returns the timing of each step, the number of
+// seconds it takes to complete all steps, and the
+// critical path: the longest chain of steps, where each
+// step is a prerequisite of the next one. Delaying any
+// step on the critical path delays the completion of all
+// steps, so the steps on it have no slack.
+//
+// See: https://en.wikipedia.org/wiki/Critical_path_method
+func (g *graph) criticalPath(duration func(string) int) (map[string]timing, int, []string, error) {
+	order, err := g.topologicalOrder()
+
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	timings := make(map[string]timing)
+
+	totalTime := 0
+
+	// Walk forward through the steps: a step can begin
+	// as soon as all its prerequisites have ended.
+	for _, name := range order {
+		t := timing{}
+
+		for prerequisite := range g.prerequisites[name] {
+			t.earliestStart = max(t.earliestStart, timings[prerequisite].earliestEnd)
+		}
+
+		t.earliestEnd = t.earliestStart + duration(name)
+
+		timings[name] = t
+
+		totalTime = max(totalTime, t.earliestEnd)
+	}
+
+	// Walk backward through the steps: a step must end
+	// before any step that depends on it must begin.
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+
+		t := timings[name]
+
+		t.latestEnd = totalTime
+
+		for dependent := range g.dependents[name] {
+			t.latestEnd = min(t.latestEnd, timings[dependent].latestStart)
+		}
+
+		t.latestStart = t.latestEnd - duration(name)
+
+		timings[name] = t
+	}
+
+	// Follow the steps without slack, starting at the
+	// first step alphabetically that begins at 0.
+	var path []string
+
+	for _, name := range order {
+		if timings[name].slack() == 0 && timings[name].earliestStart == 0 {
+			path = append(path, name)
+
+			break
+		}
+	}
+
+	for len(path) != 0 {
+		last := path[len(path)-1]
+
+		next := ""
+
+		for dependent := range g.dependents[last] {
+			t := timings[dependent]
+
+			if t.slack() == 0 && t.earliestStart == timings[last].earliestEnd && (next == "" || dependent < next) {
+				next = dependent
+			}
+		}
+
+		if next == "" {
+			break
+		}
+
+		path = append(path, next)
+	}
+
+	return timings, totalTime, path, nil
+}
@@ -0,0 +1,74 @@
+package day07
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+func init() {
+	aoc.Register(2018, 7, 2, partTwo)
+}
+
+// partTwo determines how long it takes the workers
+// to complete all of the steps in input.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 7 part 2", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// According to the puzzle, there are 5 workers and
+	// each step takes 60 seconds plus an amount that
+	// corresponds to its letter. The example uses 2
+	// workers and 0 seconds, so we make both
+	// configurable.
+	workers := flags.Int("workers", 5, "number of workers")
+	base := flags.Int("base", 60, "number of seconds each step takes before adding its letter")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	// Without workers no step is ever completed, and
+	// a negative base would make steps take a
+	// negative number of seconds.
+	if *workers < 1 {
+		return "", fmt.Errorf("need at least 1 worker, got %d", *workers)
+	}
+
+	if *base < 0 {
+		return "", fmt.Errorf("base must not be negative, got %d", *base)
+	}
+
+	// See steps.go for how the steps are parsed
+	steps, err := parseSteps(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	// See steps.go for how the workers are simulated
+	schedule, err := simulate(ctx, steps, *workers, stepDuration(*base))
+
+	if err != nil {
+		return "", err
+	}
+
+	// Print which step each worker
+	// works on, each second.
+	for _, line := range schedule.table() {
+		fmt.Fprintln(output, line)
+	}
+
+	fmt.Fprintln(output, "It will take", schedule.totalTime, "seconds to complete all of the steps.")
+
+	// The final answer
+	return strconv.Itoa(schedule.totalTime), nil
+}
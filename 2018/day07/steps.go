@@ -0,0 +1,340 @@
+// Package day07 solves the puzzle of day 7, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 7
+//
+// Part two accepts the flags -workers and -base, see
+// part_two.go. For example, for the example in the
+// README:
+//
+//	go run ./cmd/aoc run -input 2018/day07/example.txt 2018 7 2 -workers 2 -base 0
+package day07
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+// parseInstruction parses the prerequisite and step
+// from this instruction, which looks like the
+// following:
+//
+//	Step G must be finished before step Z can begin.
+//	     ^                              ^
+//	     prerequisite                   step
+//
+// The names of the prerequisite and step can be of any
+// length, as long as they do not contain whitespace.
+// The words of the instruction can be separated by any
+// amount of whitespace.
+func parseInstruction(instruction string) (string, string, error) {
+	words := strings.Fields(instruction)
+
+	// The words we expect, where "" is the
+	// name of the prerequisite or step.
+	expected := []string{"Step", "", "must", "be", "finished", "before", "step", "", "can", "begin."}
+
+	if len(words) != len(expected) {
+		return "", "", fmt.Errorf("expected %d words, got %d", len(expected), len(words))
+	}
+
+	for i, word := range expected {
+		if word != "" && words[i] != word {
+			return "", "", fmt.Errorf("expected word %d to be %q, got %q", i+1, word, words[i])
+		}
+	}
+
+	prerequisite := words[1]
+	stepName := words[7]
+
+	if prerequisite == stepName {
+		return "", "", fmt.Errorf("step %s cannot be its own prerequisite", stepName)
+	}
+
+	return prerequisite, stepName, nil
+}
+
+// parseSteps parses the graph of all steps from
+// the instructions in this reader. Empty lines
+// are ignored.
+func parseSteps(reader io.Reader) (*graph, error) {
+	// Declare scanner to read from reader. Note that
+	// it skips blank lines, and each line is without
+	// surrounding whitespace and line ending.
+	scanner := parse.NewScanner(reader)
+
+	// This are all staps we can parse from reader
+	steps := newGraph()
+
+	// Iterate over each line from reader
+	for scanner.Scan() {
+		// Line from reader
+		var instruction = scanner.Line()
+
+		prerequisite, stepName, err := parseInstruction(instruction.Text)
+
+		if err != nil {
+			return nil, instruction.Errorf("%v: %q", err, instruction.Text)
+		}
+
+		steps.addEdge(prerequisite, stepName)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// joinSteps joins these names of steps, like the
+// answer of part one: "CABDFE". If any name is
+// longer than one character, that would be
+// ambiguous, so the names are separated by a
+// space instead.
+func joinSteps(names []string) string {
+	for _, name := range names {
+		if len(name) > 1 {
+			return strings.Join(names, " ")
+		}
+	}
+
+	return strings.Join(names, "")
+}
+
+// stepDuration returns the number of seconds it takes
+// to complete the step with this name: base seconds
+// plus an amount corresponding to its letter, where
+// A=1, B=2, C=3, and so on.
+//
+// If the name consists of more than one letter, the
+// amounts of all its letters are added. Characters
+// other than "A" up to and including "Z" do not
+// add anything.
+func stepDuration(base int) func(string) int {
+	return func(stepName string) int {
+		duration := base
+
+		for _, letter := range stepName {
+			if letter >= 'A' && letter <= 'Z' {
+				duration += int(letter-'A') + 1
+			}
+		}
+
+		return duration
+	}
+}
+
+// assignment is a step that a worker works on,
+// from start up to but not including end.
+type assignment struct {
+	worker   int
+	stepName string
+	start    int
+	end      int
+}
+
+// schedule is the result of simulating
+// workers completing steps.
+type schedule struct {
+	workers int
+
+	// All assignments, in the order they started
+	assignments []assignment
+
+	// Names of the steps, in the order
+	// they were completed.
+	completed []string
+
+	// The second at which all steps are completed
+	totalTime int
+}
+
+// simulate simulates this number of workers completing
+// these steps, where each step takes duration seconds.
+//
+// Instead of advancing one second at a time, we jump
+// straight to the next second at which a worker
+// completes its step. Only then can new steps become
+// available and idle workers begin them, still in
+// alphabetical order. Like topologicalOrder in
+// graph.go, we keep the available steps in a heap.
+//
+// Each jump visits every worker, so with many workers
+// and steps this can take a while. We stop once ctx is
+// done and return its error.
+func simulate(ctx context.Context, steps *graph, workers int, duration func(string) int) (*schedule, error) {
+	// Make a map where:
+	// - key: name of step
+	// - value: number of prerequisites of key
+	//          that are not completed yet
+	remaining := steps.inDegrees()
+
+	availableSteps := &stepHeap{}
+
+	for stepName, count := range remaining {
+		if count == 0 {
+			heap.Push(availableSteps, stepName)
+		}
+	}
+
+	s := &schedule{workers: workers}
+
+	// Step that each worker works on, where
+	// nil means that the worker is idle.
+	busy := make([]*assignment, workers)
+
+	for time := 0; ; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Idle workers begin the available steps
+		// in alphabetical order.
+		for worker := 0; worker < workers && availableSteps.Len() != 0; worker++ {
+			if busy[worker] != nil {
+				continue
+			}
+
+			stepName := heap.Pop(availableSteps).(string)
+
+			busy[worker] = &assignment{
+				worker:   worker,
+				stepName: stepName,
+				start:    time,
+				end:      time + duration(stepName)}
+		}
+
+		// Determine the next second at which
+		// a worker completes its step.
+		next := -1
+
+		for _, a := range busy {
+			if a != nil && (next == -1 || a.end < next) {
+				next = a.end
+			}
+		}
+
+		if next == -1 {
+			// All workers are idle and no step is
+			// available, so we are done.
+			break
+		}
+
+		time = next
+
+		// Complete all steps that end at this second,
+		// in alphabetical order.
+		var completedSteps []string
+
+		for worker, a := range busy {
+			if a != nil && a.end == time {
+				s.assignments = append(s.assignments, *a)
+				completedSteps = append(completedSteps, a.stepName)
+				busy[worker] = nil
+			}
+		}
+
+		sort.Strings(completedSteps)
+
+		for _, stepName := range completedSteps {
+			s.completed = append(s.completed, stepName)
+
+			for dependent := range steps.dependents[stepName] {
+				remaining[dependent]--
+
+				if remaining[dependent] == 0 {
+					heap.Push(availableSteps, dependent)
+				}
+			}
+		}
+
+		s.totalTime = time
+	}
+
+	if len(s.completed) != len(remaining) {
+		return nil, steps.findCycle(remaining)
+	}
+
+	// Order the assignments by the second they started
+	sort.SliceStable(s.assignments, func(i, j int) bool {
+		return s.assignments[i].start < s.assignments[j].start
+	})
+
+	return s, nil
+}
+
+// table returns the second by second overview of which
+// step each worker works on, like in the README:
+//
+//	Second   Worker 1   Worker 2   Done
+//	   0        C          .
+//	   1        C          .
+//	   2        C          .
+//	   3        A          F       C
+//
+// The columns of the workers grow with the
+// longest name of a step.
+func (s *schedule) table() []string {
+	width := len("Worker 1")
+
+	for _, stepName := range s.completed {
+		width = max(width, len(stepName)+1)
+	}
+
+	header := "Second   "
+
+	for worker := 1; worker <= s.workers; worker++ {
+		header += fmt.Sprintf("%-*s", width+3, fmt.Sprintf("Worker %d", worker))
+	}
+
+	lines := []string{header + "Done"}
+
+	// Steps completed up to the current second
+	var done []string
+
+	for second := 0; second <= s.totalTime; second++ {
+		line := fmt.Sprintf("%4d     ", second)
+
+		for worker := 0; worker < s.workers; worker++ {
+			stepName := "."
+
+			for _, a := range s.assignments {
+				if a.worker == worker && a.start <= second && second < a.end {
+					stepName = a.stepName
+				}
+			}
+
+			line += fmt.Sprintf("   %-*s", width, stepName)
+		}
+
+		// Steps that ended at or before this second
+		// are done.
+		for len(done) < len(s.completed) && s.endOf(s.completed[len(done)]) <= second {
+			done = append(done, s.completed[len(done)])
+		}
+
+		lines = append(lines, strings.TrimRight(line+joinSteps(done), " "))
+	}
+
+	return lines
+}
+
+// endOf returns the second at which the step
+// with this name is completed.
+func (s *schedule) endOf(stepName string) int {
+	for _, a := range s.assignments {
+		if a.stepName == stepName {
+			return a.end
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,128 @@
+package day07
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// example is the list of instructions of the
+// example in the README.
+const example = `Step C must be finished before step A can begin.
+Step C must be finished before step F can begin.
+Step A must be finished before step B can begin.
+Step A must be finished before step D can begin.
+Step B must be finished before step E can begin.
+Step D must be finished before step E can begin.
+Step F must be finished before step E can begin.
+`
+
+// TestExample validates both parts against the
+// example in the README.
+func TestExample(t *testing.T) {
+	tests := []struct {
+		name     string
+		solve    aoc.Solver
+		args     []string
+		expected string
+	}{
+		{"part one", partOne, nil, "CABDFE"},
+		{"part two", partTwo, []string{"-workers", "2", "-base", "0"}, "15"},
+	}
+
+	for _, test := range tests {
+		answer, err := test.solve(context.Background(), strings.NewReader(example), test.args, io.Discard)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if answer != test.expected {
+			t.Errorf("%s: answer is %s, expected %s", test.name, answer, test.expected)
+		}
+	}
+}
+
+// TestInvalidFlags validates that part two rejects
+// flags it cannot simulate.
+func TestInvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-workers", "0"},
+		{"-workers", "-1"},
+		{"-base", "-100"},
+	} {
+		if _, err := partTwo(context.Background(), strings.NewReader(example), args, io.Discard); err == nil {
+			t.Errorf("part two accepted %q", args)
+		}
+	}
+}
+
+// TestCycle validates that both parts report steps
+// that depend on each other in a cycle, also when a
+// step outside the cycle leads into it.
+func TestCycle(t *testing.T) {
+	cyclic := `Step A must be finished before step B can begin.
+Step B must be finished before step C can begin.
+Step C must be finished before step A can begin.
+Step X must be finished before step A can begin.
+`
+
+	expected := "steps depend on each other in a cycle: A -> B -> C -> A"
+
+	tests := []struct {
+		name  string
+		solve aoc.Solver
+	}{
+		{"part one", partOne},
+		{"part two", partTwo},
+	}
+
+	for _, test := range tests {
+		_, err := test.solve(context.Background(), strings.NewReader(cyclic), nil, io.Discard)
+
+		var cycle *cycleError
+
+		if !errors.As(err, &cycle) {
+			t.Errorf("%s returned error %v, expected a *cycleError", test.name, err)
+		} else if cycle.Error() != expected {
+			t.Errorf("%s returned error %q, expected %q", test.name, cycle.Error(), expected)
+		}
+	}
+}
+
+// TestMermaidLabel validates that mermaid escapes the
+// names of steps by their entity codes.
+func TestMermaidLabel(t *testing.T) {
+	steps, err := parseSteps(strings.NewReader(`Step "A" must be finished before step #quot; can begin.
+Step <B> must be finished before step "A" can begin.
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `graph LR
+    step0["#quot;A#quot;"]
+    step1["#35;quot;"]
+    step2["#60;B#62;"]
+    step0 --> step1
+    step2 --> step0
+`
+
+	if got := steps.mermaid(); got != expected {
+		t.Errorf("mermaid returned:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+// TestSimulateCanceled validates that part two stops
+// once its context is done.
+func TestSimulateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := partTwo(ctx, strings.NewReader(example), nil, io.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("part two returned error %v, expected %v", err, context.Canceled)
+	}
+}
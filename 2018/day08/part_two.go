@@ -0,0 +1,50 @@
+package day08
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+func init() {
+	aoc.Register(2018, 8, 2, partTwo)
+}
+
+// partTwo calculates the value of the root node of the
+// tree in the license file in input.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	if err := aoc.NoArgs(args); err != nil {
+		return "", err
+	}
+
+	// See tree.go for how the numbers
+	// are read.
+	splittedData, err := readNumbers(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	// See tree.go for how the tree is decoded
+	rootNode, err := decodeTree(ctx, splittedData)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Value of the root node.
+	// This will be our final answer.
+	value, err := calculateValue(ctx, rootNode)
+
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintln(output, "The value of the root node is", value)
+
+	// The final answer
+	return strconv.Itoa(value), nil
+}
@@ -0,0 +1,406 @@
+// Package day08 solves the puzzle of day 8, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 8
+//
+// Part one accepts the flag -diagram, which draws the
+// tree like the example in the README:
+//
+//	go run ./cmd/aoc run 2018 8 1 -diagram
+package day08
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+type node struct {
+	index                     int
+	quantityOfChildNodes      int
+	quantityOfMetadataEntries int
+	childNodes                []node
+	metadata                  []int
+}
+
+// calculateSumOfMetadataEntries calculates the
+// sum of all metadata entries of this node
+// and all child nodes of this node.
+//
+// Instead of recursing into each child node, we keep
+// the nodes we still have to visit on a stack, so
+// even very deep trees fit in our memory.
+func calculateSumOfMetadataEntries(root node) int {
+	// Initial sum is 0
+	sum := 0
+
+	stack := []*node{&root}
+
+	for len(stack) != 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// Iterate over the metadata of
+		// this node and add its value
+		// to sum.
+		for _, v := range current.metadata {
+			sum = sum + v
+		}
+
+		// Visit the child nodes of this
+		// node later on.
+		for i := range current.childNodes {
+			stack = append(stack, &current.childNodes[i])
+		}
+	}
+
+	return sum
+}
+
+// nodesPerCheck is the number of nodes between two
+// checks of whether we should stop. Checking each node
+// would make decoding noticeably slower.
+const nodesPerCheck = 1 << 16
+
+// decodeTree decodes the tree from these numbers and
+// returns its root node.
+//
+// A node always consist of:
+//   - A header, which is always exactly two integers:
+//     the quantity of child nodes and the quantity of
+//     metadata entries
+//   - Zero or more child nodes (as specified in the
+//     header)
+//   - One or more metadata entries (as specified in
+//     the header)
+//
+// We read the numbers only once, from left to right.
+// Instead of recursing into each child node, we keep
+// the nodes of which we have not read all child nodes
+// yet on a stack. When we have read the metadata
+// entries of the node on top of the stack, it is
+// complete and becomes a child node of the node
+// below it.
+//
+// It returns an error if the numbers end before the
+// root node is complete, or if there are numbers left
+// after it. A license file can hold a huge tree, so we
+// also stop once ctx is done and return its error.
+func decodeTree(ctx context.Context, numbers []int) (node, error) {
+	// Each frame is a node of which we have not read
+	// all child nodes yet, and how many are left.
+	type frame struct {
+		node           node
+		childNodesLeft int
+	}
+
+	var stack []frame
+
+	// Index in numbers of the next
+	// number we will read.
+	cursor := 0
+
+	// Number of nodes of which we
+	// have read the header.
+	nodes := 0
+
+	// readHeader reads the header of the next node
+	// and pushes that node on the stack.
+	readHeader := func() error {
+		if nodes%nodesPerCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		nodes++
+
+		if len(numbers)-cursor < 2 {
+			return fmt.Errorf("truncated input: header of node at index %d needs 2 numbers, got %d", cursor, len(numbers)-cursor)
+		}
+
+		n := node{
+			index:                     cursor,
+			quantityOfChildNodes:      numbers[cursor],
+			quantityOfMetadataEntries: numbers[cursor+1]}
+
+		if n.quantityOfChildNodes < 0 || n.quantityOfMetadataEntries < 0 {
+			return fmt.Errorf("invalid header of node at index %d: %d %d", cursor, n.quantityOfChildNodes, n.quantityOfMetadataEntries)
+		}
+
+		cursor = cursor + 2
+
+		stack = append(stack, frame{node: n, childNodesLeft: n.quantityOfChildNodes})
+
+		return nil
+	}
+
+	if err := readHeader(); err != nil {
+		return node{}, err
+	}
+
+	for {
+		top := &stack[len(stack)-1]
+
+		if top.childNodesLeft > 0 {
+			// The next number is the header
+			// of the next child node.
+			top.childNodesLeft--
+
+			if err := readHeader(); err != nil {
+				return node{}, err
+			}
+
+			continue
+		}
+
+		// All child nodes are read, so the next
+		// numbers are the metadata entries.
+		quantity := top.node.quantityOfMetadataEntries
+
+		if len(numbers)-cursor < quantity {
+			return node{}, fmt.Errorf("truncated input: node at index %d needs %d metadata entries, got %d", top.node.index, quantity, len(numbers)-cursor)
+		}
+
+		top.node.metadata = numbers[cursor : cursor+quantity : cursor+quantity]
+
+		cursor = cursor + quantity
+
+		complete := top.node
+
+		stack = stack[:len(stack)-1]
+
+		if len(stack) == 0 {
+			// The root node is complete
+			if cursor != len(numbers) {
+				return node{}, fmt.Errorf("overlong input: %d numbers left after root node, starting at index %d", len(numbers)-cursor, cursor)
+			}
+
+			return complete, nil
+		}
+
+		parent := &stack[len(stack)-1].node
+
+		parent.childNodes = append(parent.childNodes, complete)
+	}
+}
+
+// readNumbers reads the numbers of the license file
+// from this reader.
+//
+// The license file should look like a huge string with
+// integers, seperated by whitespace. For example:
+//
+// 9 1 2 1 2 1 1 1 3 3 1 3 1 3 4
+//
+// Any amount of whitespace is allowed between, before
+// and after the numbers, including newlines. See
+// parse.IntFields for how we read them without the
+// license file having to fit in our memory. If a
+// number cannot be parsed, the error contains its
+// byte offset, as well as its line and column.
+func readNumbers(reader io.Reader) ([]int, error) {
+	return parse.IntFields(reader)
+}
+
+// calculateValue calculates the value of this node.
+//
+// If this node has no child nodes, its value is the
+// sum of its metadata entries. Otherwise, each metadata
+// entry refers to a child node, where 1 refers to the
+// first child node, 2 to the second, and so on. The
+// value of this node is then the sum of the values of
+// the child nodes referenced by the metadata entries.
+// A reference to a child node that does not exist,
+// including 0, is skipped.
+//
+// The value of a node depends on the values of its
+// child nodes. So we first collect all nodes in an
+// order where each node comes before its child nodes,
+// then calculate their values in reverse order.
+//
+// Like decodeTree, it stops once ctx is done and
+// returns its error.
+func calculateValue(ctx context.Context, root node) (int, error) {
+	order := []*node{&root}
+
+	for i := 0; i < len(order); i++ {
+		if i%nodesPerCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		for j := range order[i].childNodes {
+			order = append(order, &order[i].childNodes[j])
+		}
+	}
+
+	// Make a map where:
+	// - key: node
+	// - value: value of key
+	values := make(map[*node]int)
+
+	for i := len(order) - 1; i >= 0; i-- {
+		if i%nodesPerCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		current := order[i]
+
+		value := 0
+
+		for _, v := range current.metadata {
+			if len(current.childNodes) == 0 {
+				value = value + v
+			} else if v >= 1 && v <= len(current.childNodes) {
+				// A child node can be referenced multiple
+				// times, its value counts each time.
+				value = value + values[&current.childNodes[v-1]]
+			}
+		}
+
+		values[current] = value
+	}
+
+	return values[&root], nil
+}
+
+// span is where a node is written in an encoded tree
+type span struct {
+	depth int
+
+	// Offsets of the first character of the node,
+	// and of the character after its last one.
+	start int
+	end   int
+}
+
+// encode encodes the tree of this root node back to
+// numbers separated by a " ", like the license file.
+//
+// It also returns where each node is written, in the
+// order the nodes are written.
+func encode(root node) (string, []span) {
+	var builder strings.Builder
+
+	// writeNumber writes this number,
+	// separated from the previous one.
+	writeNumber := func(number int) {
+		if builder.Len() != 0 {
+			builder.WriteString(" ")
+		}
+
+		builder.WriteString(strconv.Itoa(number))
+	}
+
+	// Each frame is a node of which we have not written
+	// all child nodes yet, and the index in spans of
+	// where that node is written.
+	type frame struct {
+		node          *node
+		nextChildNode int
+		indexOfSpan   int
+	}
+
+	var spans []span
+
+	// push writes the header of this node and
+	// pushes it on the stack.
+	var stack []frame
+
+	push := func(n *node) {
+		start := builder.Len()
+
+		if start != 0 {
+			// The node starts after the separator
+			start++
+		}
+
+		writeNumber(len(n.childNodes))
+		writeNumber(len(n.metadata))
+
+		stack = append(stack, frame{node: n, indexOfSpan: len(spans)})
+		spans = append(spans, span{depth: len(stack) - 1, start: start})
+	}
+
+	push(&root)
+
+	for len(stack) != 0 {
+		top := &stack[len(stack)-1]
+
+		if top.nextChildNode < len(top.node.childNodes) {
+			top.nextChildNode++
+
+			push(&top.node.childNodes[top.nextChildNode-1])
+
+			continue
+		}
+
+		for _, v := range top.node.metadata {
+			writeNumber(v)
+		}
+
+		spans[top.indexOfSpan].end = builder.Len()
+
+		stack = stack[:len(stack)-1]
+	}
+
+	return builder.String(), spans
+}
+
+// diagram draws the tree of this root node like the
+// example in the README:
+//
+//	2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2
+//	A----------------------------------
+//	    B----------- C-----------
+//	                     D-----
+//
+// Each node is underlined on the line of its depth,
+// starting with a letter for easier identification.
+// There are only 26 letters, so after "Z" we start
+// over at "A".
+func diagram(root node) string {
+	encoded, spans := encode(root)
+
+	var lines [][]byte
+
+	for i, s := range spans {
+		for len(lines) <= s.depth {
+			lines = append(lines, []byte{})
+		}
+
+		line := lines[s.depth]
+
+		// Pad this line up to the start of this node
+		for len(line) < s.start {
+			line = append(line, ' ')
+		}
+
+		line = append(line, byte('A'+i%26))
+
+		for len(line) < s.end {
+			line = append(line, '-')
+		}
+
+		lines[s.depth] = line
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(encoded + "\n")
+
+	for _, line := range lines {
+		builder.WriteString(string(line) + "\n")
+	}
+
+	return builder.String()
+}
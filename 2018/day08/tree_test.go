@@ -0,0 +1,224 @@
+package day08
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// example is the license file of the
+// example in the README.
+const example = "2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2"
+
+// decodeExample decodes the tree of example
+func decodeExample(t *testing.T) node {
+	t.Helper()
+
+	numbers, err := readNumbers(strings.NewReader(example))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := decodeTree(context.Background(), numbers)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+// TestExample validates both parts against the
+// example in the README.
+func TestExample(t *testing.T) {
+	root := decodeExample(t)
+
+	if sum := calculateSumOfMetadataEntries(root); sum != 138 {
+		t.Errorf("sum of metadata entries of the example is %d, expected 138", sum)
+	}
+
+	value, err := calculateValue(context.Background(), root)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 66 {
+		t.Errorf("value of the example is %d, expected 66", value)
+	}
+}
+
+// TestCanceled validates that decodeTree and
+// calculateValue stop once their context is done.
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	numbers, err := readNumbers(strings.NewReader(example))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := decodeTree(ctx, numbers); !errors.Is(err, context.Canceled) {
+		t.Errorf("decodeTree returned error %v, expected %v", err, context.Canceled)
+	}
+
+	if _, err := calculateValue(ctx, decodeExample(t)); !errors.Is(err, context.Canceled) {
+		t.Errorf("calculateValue returned error %v, expected %v", err, context.Canceled)
+	}
+}
+
+// TestDiagram validates that diagram draws the
+// example like the README.
+func TestDiagram(t *testing.T) {
+	expected := `2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2
+A----------------------------------
+    B----------- C-----------
+                     D-----
+`
+
+	if got := diagram(decodeExample(t)); got != expected {
+		t.Errorf("diagram drew:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+// generateTree generates a random tree of at most this
+// depth, where each node has at most branching child
+// nodes and one up to and including three metadata
+// entries.
+//
+// About half of the metadata entries refer to a child
+// node, existing or not, so calculateValue has work
+// to do. The others are any number below 100.
+func generateTree(random *rand.Rand, depth int, branching int) node {
+	n := node{}
+
+	if depth > 1 && branching > 0 {
+		childNodes := random.Intn(branching + 1)
+
+		for i := 0; i < childNodes; i++ {
+			n.childNodes = append(n.childNodes, generateTree(random, depth-1, branching))
+		}
+	}
+
+	metadataEntries := 1 + random.Intn(3)
+
+	for i := 0; i < metadataEntries; i++ {
+		if random.Intn(2) == 0 {
+			n.metadata = append(n.metadata, random.Intn(branching+2))
+		} else {
+			n.metadata = append(n.metadata, random.Intn(100))
+		}
+	}
+
+	n.quantityOfChildNodes = len(n.childNodes)
+	n.quantityOfMetadataEntries = len(n.metadata)
+
+	return n
+}
+
+// check encodes this tree, and validates that
+// decodeTree handles the encoded tree and
+// broken variants of it.
+func check(tree node) error {
+	encoded, _ := encode(tree)
+
+	numbers, err := readNumbers(strings.NewReader(encoded))
+
+	if err != nil {
+		return err
+	}
+
+	decoded, err := decodeTree(context.Background(), numbers)
+
+	if err != nil {
+		return fmt.Errorf("decoding %q: %v", encoded, err)
+	}
+
+	if reencoded, _ := encode(decoded); reencoded != encoded {
+		return fmt.Errorf("decoding %q produced %q", encoded, reencoded)
+	}
+
+	if calculateSumOfMetadataEntries(decoded) != calculateSumOfMetadataEntries(tree) {
+		return fmt.Errorf("sum of metadata entries of %q changed after decoding", encoded)
+	}
+
+	decodedValue, err := calculateValue(context.Background(), decoded)
+
+	if err != nil {
+		return err
+	}
+
+	if value, _ := calculateValue(context.Background(), tree); decodedValue != value {
+		return fmt.Errorf("value of %q changed after decoding", encoded)
+	}
+
+	if _, err := decodeTree(context.Background(), numbers[:len(numbers)-1]); err == nil {
+		return fmt.Errorf("decoding %q without its last number did not fail", encoded)
+	}
+
+	if _, err := decodeTree(context.Background(), append(numbers, 0)); err == nil {
+		return fmt.Errorf("decoding %q with an extra number did not fail", encoded)
+	}
+
+	return nil
+}
+
+// TestRoundTrip validates that encoding and decoding
+// many random trees produces the same trees.
+func TestRoundTrip(t *testing.T) {
+	// Use a fixed seed, so every run
+	// checks the same trees.
+	random := rand.New(rand.NewSource(8))
+
+	for i := 0; i < 10000; i++ {
+		tree := generateTree(random, 1+random.Intn(6), random.Intn(5))
+
+		if err := check(tree); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// FuzzDecodeTree validates that decodeTree never
+// panics, and that each tree it decodes survives
+// the same checks as the random trees. Run it with:
+//
+//	go test -fuzz FuzzDecodeTree ./2018/day08
+//
+// The corpus starts with the example and random
+// trees, encoded like the license file.
+func FuzzDecodeTree(f *testing.F) {
+	f.Add(example)
+
+	random := rand.New(rand.NewSource(8))
+
+	for i := 0; i < 100; i++ {
+		encoded, _ := encode(generateTree(random, 1+random.Intn(6), random.Intn(5)))
+
+		f.Add(encoded)
+	}
+
+	f.Fuzz(func(t *testing.T, licenseFile string) {
+		numbers, err := readNumbers(strings.NewReader(licenseFile))
+
+		if err != nil {
+			return
+		}
+
+		tree, err := decodeTree(context.Background(), numbers)
+
+		if err != nil {
+			return
+		}
+
+		if err := check(tree); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+// Package day09 solves the puzzle of day 9, see the
+// README. Run it from the root of the repository
+// with:
+//
+//	go run ./cmd/aoc run 2018 9
+//
+// Both parts accept the flags -modulus and -offset for
+// variants of the game. Part one also accepts -circles
+// and -timeline, see part_one.go. For example:
+//
+//	go run ./cmd/aoc run 2018 9 1 -timeline scores.csv
+package day09
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/TonnyGaric/adventofcode/internal/parse"
+)
+
+// game describes a marble game. The puzzle always
+// uses a scoringModulus of 23 and a removalOffset
+// of 7, but variants of the game can differ.
+type game struct {
+	numberOfPlayers int
+
+	// Value of the last marble that is placed
+	lastMarble int
+
+	// A marble with a value that is a multiple of
+	// scoringModulus is not placed, but kept by the
+	// current player, adding it to their score.
+	scoringModulus int
+
+	// When a marble is kept, the marble removalOffset
+	// marbles counter-clockwise from the current marble
+	// is removed and also added to the player's score.
+	removalOffset int
+}
+
+// description is a game as described on a
+// line of the input, for example:
+//
+// 10 players; last marble is worth 1618 points: high score is 8317
+//
+// The high score at the end is optional.
+type description struct {
+	lineNumber int
+
+	numberOfPlayers int
+	lastMarble      int
+
+	// The expected high score, or -1
+	// if the line does not give one.
+	highScore int
+}
+
+// descriptionPattern matches a description of a game
+var descriptionPattern = parse.MustCompile(`^(\d+) players; last marble is worth (\d+) points(?:: high score is (\d+))?$`, "10 players; last marble is worth 1618 points")
+
+// parseDescription parses the description of a
+// game from this line.
+func parseDescription(line parse.Line) (description, error) {
+	groups, err := descriptionPattern.Match(line)
+
+	if err != nil {
+		return description{}, err
+	}
+
+	d := description{lineNumber: line.Number, highScore: -1}
+
+	// The pattern only matches digits, so converting
+	// can only fail if the number is too large.
+	if d.numberOfPlayers, err = line.Atoi(groups[0], "number of players"); err != nil {
+		return description{}, err
+	}
+
+	if d.lastMarble, err = line.Atoi(groups[1], "value of last marble"); err != nil {
+		return description{}, err
+	}
+
+	if groups[2] != "" {
+		if d.highScore, err = line.Atoi(groups[2], "high score"); err != nil {
+			return description{}, err
+		}
+	}
+
+	return d, nil
+}
+
+// readDescriptions reads the descriptions of all
+// games from reader, one game per line.
+func readDescriptions(reader io.Reader) ([]description, error) {
+	descriptions, err := parse.Map(reader, parseDescription)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(descriptions) == 0 {
+		return nil, errors.New("no games found")
+	}
+
+	return descriptions, nil
+}
+
+// validate validates that this game can be played
+func (g game) validate() error {
+	if g.numberOfPlayers < 1 {
+		return fmt.Errorf("a game needs at least 1 player, got %d", g.numberOfPlayers)
+	}
+
+	if g.lastMarble < 0 {
+		return fmt.Errorf("the last marble cannot be negative, got %d", g.lastMarble)
+	}
+
+	// With a scoring modulus of 1, the first turn
+	// already removes marble 0, which leaves the
+	// circle without any marbles.
+	if g.scoringModulus < 2 {
+		return fmt.Errorf("the scoring modulus must be at least 2, got %d", g.scoringModulus)
+	}
+
+	if g.removalOffset < 0 {
+		return fmt.Errorf("the removal offset cannot be negative, got %d", g.removalOffset)
+	}
+
+	return nil
+}
+
+// circle is a game in progress.
+//
+// Instead of allocating a marble for each value, the
+// circle is kept in two slices, where the index is the
+// value of a marble: next holds the value of the marble
+// clockwise of it, and previous the value of the marble
+// counter-clockwise of it.
+type circle struct {
+	game game
+
+	// Score of each player
+	players []int
+
+	next     []int
+	previous []int
+
+	currentMarble int
+
+	// Value of the last marble that was placed or kept
+	lastValue int
+}
+
+// newCircle creates a circle for this game, with only the
+// marble 0 in it. The slices of the circle are allocated
+// up front for capacity marbles, more marbles can still
+// be placed later on.
+func newCircle(g game, capacity int) *circle {
+	return &circle{
+		game: g,
+
+		// Create a slice of ints of length
+		// of number of players, to keep
+		// track of the score of each
+		// player.
+		players: make([]int, g.numberOfPlayers),
+
+		// The marble with value 0 is the only marble in
+		// the circle, so it is both clockwise and counter-
+		// clockwise from itself. Both slices hold 0 for it.
+		next:     make([]int, 1, capacity+1),
+		previous: make([]int, 1, capacity+1)}
+}
+
+// turn lets the next player take a turn with the lowest-
+// numbered remaining marble. It returns the player that
+// took the turn and the points that player scored.
+func (c *circle) turn() (int, int) {
+	c.lastValue++
+
+	actualValue := c.lastValue
+
+	// This is a no-op while there is enough
+	// capacity, which play makes sure of.
+	c.next = append(c.next, 0)
+	c.previous = append(c.previous, 0)
+
+	// Keep track of the number of the
+	// current player.
+	currentPlayer := (actualValue - 1) % c.game.numberOfPlayers
+
+	// If the marble that is about to be placed
+	// has a number which is a multiple of
+	// scoringModulus, we need to apply
+	// different logic.
+	if actualValue%c.game.scoringModulus == 0 {
+		// First, the current player keeps the
+		// marble they would have placed,
+		// adding it to their score.
+		c.players[currentPlayer] += actualValue
+
+		// In addition the marble removalOffset
+		// marbles counter-clockwise from the
+		// current marble is removed from
+		// the circle.
+		removedMarble := c.currentMarble
+
+		for i := 0; i < c.game.removalOffset; i++ {
+			removedMarble = c.previous[removedMarble]
+		}
+
+		c.next[c.previous[removedMarble]] = c.next[removedMarble]
+		c.previous[c.next[removedMarble]] = c.previous[removedMarble]
+
+		// And also added to the current
+		// player's score.
+		c.players[currentPlayer] += removedMarble
+
+		// The marble located immediately
+		// clockwise of the marble that
+		// was removed becomes the new
+		// current marble.
+		c.currentMarble = c.next[removedMarble]
+
+		return currentPlayer, actualValue + removedMarble
+	}
+
+	// Place the new marble between the marbles
+	// that are 1 and 2 marbles clockwise of
+	// the current marble.
+	left := c.next[c.currentMarble]
+	right := c.next[left]
+
+	c.next[left] = actualValue
+	c.previous[actualValue] = left
+	c.next[actualValue] = right
+	c.previous[right] = actualValue
+
+	// The marble that was just placed
+	// then becomes the current
+	// marble.
+	c.currentMarble = actualValue
+
+	return currentPlayer, 0
+}
+
+// turnsPerCheck is the number of turns between two
+// checks of whether we should stop playing. Checking
+// each turn would make playing noticeably slower.
+const turnsPerCheck = 1 << 16
+
+// play plays this game and returns
+// the score of each player.
+//
+// If rec is not nil, each turn is recorded in rec.
+//
+// The circle is allocated up front for all marbles,
+// so playing does not allocate anything per marble.
+// Large games take a while, so we stop playing once
+// ctx is done and return its error.
+func (g game) play(ctx context.Context, rec *recorder) ([]int, error) {
+	c := newCircle(g, g.lastMarble)
+
+	if rec != nil {
+		rec.start(g)
+		rec.recordCircle(0, -1, c.next, c.previous, c.currentMarble)
+	}
+
+	// Place each marble, up to and
+	// including the last marble.
+	for c.lastValue < g.lastMarble {
+		if c.lastValue%turnsPerCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		player, points := c.turn()
+
+		if rec != nil {
+			if points != 0 {
+				rec.recordScore(c.lastValue, player, points, c.players)
+			}
+
+			rec.recordCircle(c.lastValue, player, c.next, c.previous, c.currentMarble)
+		}
+	}
+
+	return c.players, nil
+}
+
+// highScore returns the highest of these scores,
+// thus the winning Elf's score.
+func highScore(scores []int) int {
+	var winningElfsScore int
+
+	for _, score := range scores {
+		if score > winningElfsScore {
+			winningElfsScore = score
+		}
+	}
+
+	return winningElfsScore
+}
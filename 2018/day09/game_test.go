@@ -0,0 +1,216 @@
+package day09
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// examples are the games of the examples in
+// the README, with their high scores.
+const examples = `9 players; last marble is worth 25 points: high score is 32
+10 players; last marble is worth 1618 points: high score is 8317
+13 players; last marble is worth 7999 points: high score is 146373
+17 players; last marble is worth 1104 points: high score is 2764
+21 players; last marble is worth 6111 points: high score is 54718
+30 players; last marble is worth 5807 points: high score is 37305
+`
+
+// TestExamples validates that part one produces the
+// high score of each example in the README.
+func TestExamples(t *testing.T) {
+	answer, err := partOne(context.Background(), strings.NewReader(examples), nil, io.Discard)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "32,8317,146373,2764,54718,37305"; answer != expected {
+		t.Errorf("answer is %s, expected %s", answer, expected)
+	}
+}
+
+// TestInvalidGames validates that games which cannot
+// be played are rejected.
+func TestInvalidGames(t *testing.T) {
+	tests := []struct {
+		solve aoc.Solver
+		input string
+		args  []string
+	}{
+		{partOne, "0 players; last marble is worth 25 points", nil},
+		{partOne, "9 players; last marble is worth 25 points", []string{"-modulus", "1"}},
+		{partOne, "9 players; last marble is worth 25 points", []string{"-offset", "-1"}},
+		{partOne, "9 players; last marble is worth 25 points: high score is 33", nil},
+		{partOne, "9 players; last marble was worth 25 points", nil},
+		{partTwo, "9 players; last marble is worth 9223372036854775807 points", nil},
+	}
+
+	for _, test := range tests {
+		if _, err := test.solve(context.Background(), strings.NewReader(test.input), test.args, io.Discard); err == nil {
+			t.Errorf("%q with %q is accepted", test.input, test.args)
+		}
+	}
+}
+
+type marble struct {
+	next     *marble
+	previous *marble
+	value    int
+}
+
+// playWithPointers plays this game like play, but
+// allocates a marble for each value.
+func (g game) playWithPointers() []int {
+	// Create a slice of ints of length
+	// of number of players, to keep
+	// track of the score of each
+	// player.
+	players := make([]int, g.numberOfPlayers)
+
+	currentMarble := &marble{}
+	currentMarble.next = currentMarble
+	currentMarble.previous = currentMarble
+
+	// Keep track of the number of the
+	// current player.
+	var currentPlayer int
+
+	// Place each marble, up to and
+	// including the last marble.
+	for actualValue := 1; actualValue <= g.lastMarble; actualValue++ {
+		currentPlayer = (actualValue - 1) % g.numberOfPlayers
+
+		// If the marble that is about to be placed
+		// has a number which is a multiple of
+		// scoringModulus, we need to apply
+		// different logic.
+		if actualValue%g.scoringModulus == 0 {
+			// First, the current player keeps the
+			// marble they would have placed,
+			// adding it to their score.
+			players[currentPlayer] += actualValue
+
+			// In addition the marble removalOffset
+			// marbles counter-clockwise from the
+			// current marble is removed from
+			// the circle.
+			removedMarble := currentMarble
+
+			for i := 0; i < g.removalOffset; i++ {
+				removedMarble = removedMarble.previous
+			}
+
+			removedMarble.previous.next = removedMarble.next
+			removedMarble.next.previous = removedMarble.previous
+
+			// And also added to the current
+			// player's score.
+			players[currentPlayer] += removedMarble.value
+
+			// The marble located immediately
+			// clockwise of the marble that
+			// was removed becomes the new
+			// current marble.
+			currentMarble = removedMarble.next
+		} else {
+			// Create a new marble to place
+			marble := &marble{value: actualValue}
+
+			// Set the next and previous marble,
+			// based on the current marble.
+			marble.next = currentMarble.next.next
+			marble.previous = currentMarble.next
+
+			currentMarble.next.next.previous = marble
+			currentMarble.next.next = marble
+
+			// The marble that was just placed
+			// then becomes the current
+			// marble.
+			currentMarble = marble
+		}
+	}
+
+	return players
+}
+
+// partTwoGame returns the game of part two of the
+// puzzle: the first game of input.txt, with a last
+// marble that is 100 times larger.
+func partTwoGame(tb testing.TB) game {
+	tb.Helper()
+
+	inputFile, err := os.Open("input.txt")
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	defer inputFile.Close()
+
+	descriptions, err := readDescriptions(inputFile)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return game{
+		numberOfPlayers: descriptions[0].numberOfPlayers,
+		lastMarble:      descriptions[0].lastMarble * 100,
+		scoringModulus:  23,
+		removalOffset:   7}
+}
+
+// TestPlayWithPointers validates that play and
+// playWithPointers produce the same scores.
+func TestPlayWithPointers(t *testing.T) {
+	g := partTwoGame(t)
+
+	// Part two takes a while with pointers, so we
+	// play a game that is 10 times smaller.
+	g.lastMarble /= 10
+
+	slices, err := g.play(context.Background(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointers := g.playWithPointers()
+
+	for player := range slices {
+		if slices[player] != pointers[player] {
+			t.Fatalf("score of player %d is %d, expected %d", player, slices[player], pointers[player])
+		}
+	}
+}
+
+// BenchmarkPlay plays part two with play
+func BenchmarkPlay(b *testing.B) {
+	g := partTwoGame(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.play(context.Background(), nil)
+	}
+}
+
+// BenchmarkPlayWithPointers plays part two with
+// playWithPointers, to show how much faster play
+// is without allocating a marble for each value.
+func BenchmarkPlayWithPointers(b *testing.B) {
+	g := partTwoGame(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.playWithPointers()
+	}
+}
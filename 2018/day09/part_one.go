@@ -1,146 +1,158 @@
-package main
+package day09
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
 )
 
-type marble struct {
-	next     *marble
-	previous *marble
-	value    int
+func init() {
+	aoc.Register(2018, 9, 1, partOne)
 }
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
+// partOne plays each game described in input, and
+// returns the winning Elf's score. If input describes
+// more than one game, it returns the score of each
+// game, separated by commas.
+func partOne(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 9 part 1", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// The puzzle keeps each marble that is a multiple
+	// of 23 and removes the marble 7 counter-clockwise.
+	// Variants of the game can use other rules.
+	scoringModulus := flags.Int("modulus", 23, "keep each marble that is a multiple of this number")
+	removalOffset := flags.Int("offset", 7, "remove the marble this many marbles counter-clockwise when keeping a marble")
+
+	// Optionally, print the circle after each turn like
+	// in the README, which is only readable for small
+	// games. For any game, the scores can be written
+	// to a CSV file.
+	printCircles := flags.Bool("circles", false, "print the circle after each turn, for games of up to 100 marbles")
+	timelinePath := flags.String("timeline", "", "path of a CSV file to write the score of each player over time to")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	// See game.go for how input is read. Each line
+	// of it describes a game, like the examples in
+	// the README.
+	descriptions, err := readDescriptions(input)
 
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text. Nevertheless, note that
-	// we expect this inputFile to only have a
-	// single line.
-	scanner := bufio.NewScanner(inputFile)
-
-	// Advance the Scanner to the next token
-	scanner.Scan()
-
-	// Number of players, as parsed from this inputFile.
-	var numberOfPlayers int
-
-	// Value of last marble, as parsed from this inputFile.
-	var lastMarble int
-
-	// Sscanf scans the argument string, storing
-	// successive space-separated values into
-	// successive arguments as determined by
-	// the format.
-	// See also: https://golang.org/pkg/fmt/#Sscanf
-	fmt.Sscanf(scanner.Text(),
-		"%d players; last marble is worth %d points",
-		&numberOfPlayers,
-		&lastMarble)
-
-	// Create a slice of ints of length
-	// of number of players, to keep
-	// track of the score of each
-	// player.
-	players := make([]int, numberOfPlayers)
-
-	currentMarble := &marble{}
-	currentMarble.next = currentMarble
-	currentMarble.previous = currentMarble
-
-	// Actual value of the current marble
-	actualValue := 1
-
-	// Keep track of the number of the
-	// current player.
-	var currentPlayer int
-
-	for actualValue != lastMarble {
-		currentPlayer = (actualValue - 1) % numberOfPlayers
-
-		// If the marble that is about to be placed
-		// has a number which is a multiple of 23,
-		// we need to apply different logic.
-		if actualValue%23 == 0 {
-			// First, the current player keeps the
-			// marble they would have placed,
-			// adding it to their score.
-			players[currentPlayer] += actualValue
-
-			// In addition the marble 7 marbles
-			// counter-clockwise from the
-			// current marble is removed
-			// from the circle.
-			removedMarble := currentMarble.
-				previous. // 1
-				previous. // 2
-				previous. // 3
-				previous. // 4
-				previous. // 5
-				previous. // 6
-				previous  // 7
-
-			removedMarble.previous.next = removedMarble.next
-			removedMarble.next.previous = removedMarble.previous
-
-			// And also added to the current
-			// player's score.
-			players[currentPlayer] += removedMarble.value
-
-			// The marble located immediately
-			// clockwise of the marble that
-			// was removed becomes the new
-			// current marble.
-			currentMarble = removedMarble.next
-		} else {
-			// Create a new marble to place
-			marble := &marble{value: actualValue}
+	if *timelinePath != "" && len(descriptions) != 1 {
+		return "", fmt.Errorf("a timeline can only be written for a single game, got %d", len(descriptions))
+	}
+
+	// Keep track of whether any game did not
+	// produce the high score it describes.
+	mismatch := false
 
-			// Set the next and previous marble,
-			// based on the current marble.
-			marble.next = currentMarble.next.next
-			marble.previous = currentMarble.next
+	// The winning Elf's score of each game
+	var answers []string
 
-			currentMarble.next.next.previous = marble
-			currentMarble.next.next = marble
+	for _, d := range descriptions {
+		g := game{
+			numberOfPlayers: d.numberOfPlayers,
+			lastMarble:      d.lastMarble,
+			scoringModulus:  *scoringModulus,
+			removalOffset:   *removalOffset}
 
-			// The marble that was just placed
-			// then becomes the current
-			// marble.
-			currentMarble = marble
+		if err := g.validate(); err != nil {
+			return "", fmt.Errorf("line %d: %w", d.lineNumber, err)
 		}
 
-		// Increment the actual value
-		actualValue++
-	}
+		var rec *recorder
+
+		if *printCircles || *timelinePath != "" {
+			// See recorder.go for what is recorded
+			rec = newRecorder(100)
+		}
+
+		// See game.go for how the game is played
+		scores, err := g.play(ctx, rec)
+
+		if err != nil {
+			return "", err
+		}
+
+		winningElfsScore := highScore(scores)
+
+		if *printCircles {
+			if rec.circles == nil {
+				return "", errors.New("the game is too large to print the circle after each turn")
+			}
 
-	// Keep track of the highest score, thus
-	// the winning Elf's score. This will be
-	// our final answer.
-	var winningElfsScore int
+			for _, circle := range rec.circles {
+				fmt.Fprintln(output, circle)
+			}
+		}
+
+		if *timelinePath != "" {
+			timelineFile, err := os.Create(*timelinePath)
+
+			if err != nil {
+				return "", err
+			}
+
+			if err := rec.writeTimeline(timelineFile); err != nil {
+				timelineFile.Close()
+
+				return "", err
+			}
+
+			// Closing the file can fail to write what
+			// is left of the timeline, so we do not
+			// ignore its error.
+			if err := timelineFile.Close(); err != nil {
+				return "", err
+			}
+
+			for _, line := range rec.describeLeadChanges() {
+				fmt.Fprintln(output, line)
+			}
+		}
 
-	// Iterate over each value in map players,
-	// to determine the winning Elf's score.
-	for _, score := range players {
-		if score > winningElfsScore {
-			winningElfsScore = score
+		// Prefix the answer with the line of
+		// the game, if there are more games.
+		prefix := ""
+
+		if len(descriptions) > 1 {
+			prefix = fmt.Sprintf("%d players; last marble is worth %d points: ", d.numberOfPlayers, d.lastMarble)
+		}
+
+		answers = append(answers, strconv.Itoa(winningElfsScore))
+
+		if d.highScore == -1 {
+			fmt.Fprintf(output, "%sThe winning Elf's score is %d.\n", prefix, winningElfsScore)
+		} else if d.highScore == winningElfsScore {
+			fmt.Fprintf(output, "%sThe winning Elf's score is %d, as expected.\n", prefix, winningElfsScore)
+		} else {
+			fmt.Fprintf(output, "%sThe winning Elf's score is %d, but %d was expected.\n", prefix, winningElfsScore, d.highScore)
+
+			mismatch = true
 		}
 	}
 
-	// Print the final answer
-	fmt.Printf("The winning Elf's score is %d.\n", winningElfsScore)
+	if mismatch {
+		return "", errors.New("the winning Elf's score of a game is not the high score it describes")
+	}
+
+	// The final answer
+	return strings.Join(answers, ","), nil
 }
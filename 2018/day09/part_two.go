@@ -1,152 +1,96 @@
-package main
+package day09
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
 )
 
-type marble struct {
-	next     *marble
-	previous *marble
-	value    int
+func init() {
+	aoc.Register(2018, 9, 2, partTwo)
 }
 
-func main() {
-	// Open file "input.txt" for reading
-	inputFile, err := os.Open("input.txt")
+// partTwo plays each game described in input, with
+// a last marble that is 100 times larger, and returns
+// the winning Elf's score like partOne.
+func partTwo(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	flags := flag.NewFlagSet("2018 day 9 part 2", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	// The puzzle keeps each marble that is a multiple
+	// of 23 and removes the marble 7 counter-clockwise.
+	// Variants of the game can use other rules.
+	scoringModulus := flags.Int("modulus", 23, "keep each marble that is a multiple of this number")
+	removalOffset := flags.Int("offset", 7, "remove the marble this many marbles counter-clockwise when keeping a marble")
+
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if err := aoc.NoArgs(flags.Args()); err != nil {
+		return "", err
+	}
+
+	// See game.go for how input is read. Each line
+	// of it describes a game, like the examples in
+	// the README.
+	descriptions, err := readDescriptions(input)
 
-	// Opening file can have an error. If there is
-	// an error, it will be of type *PathError.
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	// Closes the file when we are done
-	defer inputFile.Close()
-
-	// Declare scanner to read from inputFile. Note that
-	// the split function defaults to ScanLines—which
-	// is each line of text. Nevertheless, note that
-	// we expect this inputFile to only have a
-	// single line.
-	scanner := bufio.NewScanner(inputFile)
-
-	// Advance the Scanner to the next token
-	scanner.Scan()
-
-	// Number of players, as parsed from this inputFile.
-	var numberOfPlayers int
-
-	// Value of last marble, as parsed from this inputFile.
-	var lastMarble int
-
-	// Sscanf scans the argument string, storing
-	// successive space-separated values into
-	// successive arguments as determined by
-	// the format.
-	// See also: https://golang.org/pkg/fmt/#Sscanf
-	fmt.Sscanf(scanner.Text(),
-		"%d players; last marble is worth %d points",
-		&numberOfPlayers,
-		&lastMarble)
-
-	// We need to determine what the new winning
-	// Elf's score would be if the number of the
-	// last marble were 100 times larger. So we
-	// will multiply the last marble with 100.
-	lastMarble = lastMarble * 100
-
-	// Create a slice of ints of length
-	// of number of players, to keep
-	// track of the score of each
-	// player.
-	players := make([]int, numberOfPlayers)
-
-	currentMarble := &marble{}
-	currentMarble.next = currentMarble
-	currentMarble.previous = currentMarble
-
-	// Actual value of the current marble
-	actualValue := 1
-
-	// Keep track of the number of the
-	// current player.
-	var currentPlayer int
-
-	for actualValue != lastMarble {
-		currentPlayer = (actualValue - 1) % numberOfPlayers
-
-		// If the marble that is about to be placed
-		// has a number which is a multiple of 23,
-		// we need to apply different logic.
-		if actualValue%23 == 0 {
-			// First, the current player keeps the
-			// marble they would have placed,
-			// adding it to their score.
-			players[currentPlayer] += actualValue
-
-			// In addition the marble 7 marbles
-			// counter-clockwise from the
-			// current marble is removed
-			// from the circle.
-			removedMarble := currentMarble.
-				previous. // 1
-				previous. // 2
-				previous. // 3
-				previous. // 4
-				previous. // 5
-				previous. // 6
-				previous  // 7
-
-			removedMarble.previous.next = removedMarble.next
-			removedMarble.next.previous = removedMarble.previous
-
-			// And also added to the current
-			// player's score.
-			players[currentPlayer] += removedMarble.value
-
-			// The marble located immediately
-			// clockwise of the marble that
-			// was removed becomes the new
-			// current marble.
-			currentMarble = removedMarble.next
-		} else {
-			// Create a new marble to place
-			marble := &marble{value: actualValue}
-
-			// Set the next and previous marble,
-			// based on the current marble.
-			marble.next = currentMarble.next.next
-			marble.previous = currentMarble.next
-
-			currentMarble.next.next.previous = marble
-			currentMarble.next.next = marble
-
-			// The marble that was just placed
-			// then becomes the current
-			// marble.
-			currentMarble = marble
+	// The winning Elf's score of each game
+	var answers []string
+
+	for _, d := range descriptions {
+		// We need to determine what the new winning
+		// Elf's score would be if the number of the
+		// last marble were 100 times larger. So we
+		// will multiply the last marble with 100.
+		//
+		// Note that this means that the high score
+		// a line may describe, does not apply.
+		if d.lastMarble > math.MaxInt/100 {
+			return "", fmt.Errorf("line %d: last marble %d is too large to multiply by 100", d.lineNumber, d.lastMarble)
 		}
 
-		// Increment the actual value
-		actualValue++
-	}
+		g := game{
+			numberOfPlayers: d.numberOfPlayers,
+			lastMarble:      d.lastMarble * 100,
+			scoringModulus:  *scoringModulus,
+			removalOffset:   *removalOffset}
 
-	// Keep track of the highest score, thus
-	// the winning Elf's score. This will be
-	// our final answer.
-	var winningElfsScore int
+		if err := g.validate(); err != nil {
+			return "", fmt.Errorf("line %d: %w", d.lineNumber, err)
+		}
 
-	// Iterate over each value in map players,
-	// to determine the winning Elf's score.
-	for _, score := range players {
-		if score > winningElfsScore {
-			winningElfsScore = score
+		// Prefix the answer with the line of
+		// the game, if there are more games.
+		prefix := ""
+
+		if len(descriptions) > 1 {
+			prefix = fmt.Sprintf("%d players; last marble is worth %d points: ", d.numberOfPlayers, g.lastMarble)
+		}
+
+		// See game.go for how the game is played
+		scores, err := g.play(ctx, nil)
+
+		if err != nil {
+			return "", err
 		}
+
+		answers = append(answers, strconv.Itoa(highScore(scores)))
+
+		fmt.Fprintf(output, "%sThe winning Elf's score is %d.\n", prefix, highScore(scores))
 	}
 
-	// Print the final answer
-	fmt.Printf("The winning Elf's score is %d.\n", winningElfsScore)
+	// The final answer
+	return strings.Join(answers, ","), nil
 }
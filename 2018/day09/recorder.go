@@ -0,0 +1,204 @@
+// This file holds the recorder of a game, which is
+// used by part one. See game.go for how to run
+// either part.
+
+package day09
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// scoreEvent is a turn in which a player scored
+type scoreEvent struct {
+	turn   int
+	player int
+
+	// Points scored in this turn, and the
+	// score of player after this turn.
+	points int
+	score  int
+}
+
+// leadChange is a turn in which the lead
+// changed hands.
+type leadChange struct {
+	turn int
+
+	// The player that had the lead before this turn,
+	// or -1 if nobody had scored yet, and the player
+	// that has the lead after it.
+	from int
+	to   int
+}
+
+// recorder records what happens while a game is played.
+//
+// Drawing the circle after each turn takes time and memory
+// proportional to the number of marbles in it. So circles
+// are only recorded for games with a last marble of up to
+// circleLimit. The scores are always recorded.
+type recorder struct {
+	circleLimit int
+
+	// The circle after each turn, like in the README.
+	// This is nil for games that are too large.
+	circles []string
+
+	// Width of each marble in circles
+	width int
+
+	// All turns in which a player scored
+	timeline []scoreEvent
+
+	// All turns in which the lead changed hands
+	leadChanges []leadChange
+
+	// The player that has the lead,
+	// or -1 if nobody has.
+	leader int
+}
+
+// newRecorder creates a recorder that records circles
+// for games with a last marble of up to circleLimit.
+func newRecorder(circleLimit int) *recorder {
+	return &recorder{circleLimit: circleLimit, leader: -1}
+}
+
+// start prepares this recorder for this game
+func (rec *recorder) start(g game) {
+	rec.circles = nil
+	rec.timeline = nil
+	rec.leadChanges = nil
+	rec.leader = -1
+
+	if g.lastMarble <= rec.circleLimit {
+		rec.circles = []string{}
+
+		// Each marble is right aligned, with at least
+		// one space before it, like in the README.
+		rec.width = max(3, len(strconv.Itoa(g.lastMarble))+1)
+	}
+}
+
+// recordCircle records the circle after this turn of this
+// player, where player -1 means that no player took a
+// turn yet. The circle is drawn starting at the marble 0,
+// following next, with the current marble in parentheses:
+//
+// [3]  0  2  1 (3)
+//
+// In variants of the game, the marble 0 can be removed,
+// in which case the circle starts at the current marble.
+func (rec *recorder) recordCircle(turn int, player int, next []int, previous []int, currentMarble int) {
+	if rec.circles == nil {
+		return
+	}
+
+	line := []byte("[-]")
+
+	if player != -1 {
+		// Players are numbered from 1
+		line = []byte(fmt.Sprintf("[%d]", player+1))
+	}
+
+	prefix := len(line)
+
+	first := 0
+
+	if next[previous[0]] != 0 {
+		// The marble counter-clockwise of the marble 0
+		// no longer points to it, so it is removed.
+		first = currentMarble
+	}
+
+	// After turn, there are at most turn + 1
+	// marbles in the circle.
+	for i, marble := 0, first; i <= turn && (i == 0 || marble != first); i, marble = i+1, next[marble] {
+		value := strconv.Itoa(marble)
+
+		// Right align this marble, so its last digit
+		// lines up with the marbles of the other turns.
+		end := prefix + rec.width*(i+1)
+
+		for len(line) < end-len(value) {
+			line = append(line, ' ')
+		}
+
+		if marble == currentMarble {
+			// The parentheses take the place of
+			// the spaces around this marble.
+			line[len(line)-1] = '('
+			line = append(line, value...)
+			line = append(line, ')')
+		} else {
+			line = append(line, value...)
+		}
+	}
+
+	rec.circles = append(rec.circles, string(line))
+}
+
+// recordScore records that this player scored these
+// points in this turn, where players holds the score
+// of each player after this turn.
+func (rec *recorder) recordScore(turn int, player int, points int, players []int) {
+	rec.timeline = append(rec.timeline, scoreEvent{
+		turn:   turn,
+		player: player,
+		points: points,
+		score:  players[player]})
+
+	// The lead only changes hands if this player
+	// now has a higher score than the leader. On
+	// a tie, the leader keeps the lead.
+	if player != rec.leader && (rec.leader == -1 || players[player] > players[rec.leader]) {
+		rec.leadChanges = append(rec.leadChanges, leadChange{turn: turn, from: rec.leader, to: player})
+		rec.leader = player
+	}
+}
+
+// writeTimeline writes all turns in which a player scored
+// to this writer as CSV, with the columns: turn, player,
+// points and score. Players are numbered from 1.
+func (rec *recorder) writeTimeline(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write([]string{"turn", "player", "points", "score"}); err != nil {
+		return err
+	}
+
+	for _, event := range rec.timeline {
+		record := []string{
+			strconv.Itoa(event.turn),
+			strconv.Itoa(event.player + 1),
+			strconv.Itoa(event.points),
+			strconv.Itoa(event.score)}
+
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// describeLeadChanges describes in which turns
+// the lead changed hands.
+func (rec *recorder) describeLeadChanges() []string {
+	var lines []string
+
+	for _, change := range rec.leadChanges {
+		if change.from == -1 {
+			lines = append(lines, fmt.Sprintf("Turn %d: player %d takes the lead", change.turn, change.to+1))
+		} else {
+			lines = append(lines, fmt.Sprintf("Turn %d: player %d takes the lead from player %d", change.turn, change.to+1, change.from+1))
+		}
+	}
+
+	return lines
+}
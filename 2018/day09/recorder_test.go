@@ -0,0 +1,114 @@
+package day09
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readmeCircles reads the circle after each turn of
+// the example in the README, without the spaces some
+// lines end with.
+func readmeCircles(t *testing.T) []string {
+	t.Helper()
+
+	readme, err := os.ReadFile("README.md")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var circles []string
+
+	for _, line := range strings.Split(string(readme), "\n") {
+		if strings.HasPrefix(line, "[") {
+			circles = append(circles, strings.TrimRight(line, " "))
+		}
+	}
+
+	return circles
+}
+
+// TestCircles validates that part one prints the same
+// circles as the example in the README.
+func TestCircles(t *testing.T) {
+	var output bytes.Buffer
+
+	if _, err := partOne(context.Background(), strings.NewReader("9 players; last marble is worth 25 points: high score is 32\n"), []string{"-circles"}, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.HasPrefix(line, "[") {
+			got = append(got, line)
+		}
+	}
+
+	expected := readmeCircles(t)
+
+	if len(expected) != 26 {
+		t.Fatalf("README has %d circles, expected 26", len(expected))
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("part one printed %d circles, expected %d", len(got), len(expected))
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("circle %d is:\n%s\nexpected:\n%s", i, got[i], expected[i])
+		}
+	}
+}
+
+// TestTimeline validates the timeline and lead changes
+// of a small game, in which the lead changes hands in
+// each turn that scores.
+func TestTimeline(t *testing.T) {
+	rec := newRecorder(0)
+
+	g := game{numberOfPlayers: 3, lastMarble: 100, scoringModulus: 23, removalOffset: 7}
+
+	if _, err := g.play(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.circles != nil {
+		t.Errorf("recorded %d circles for a game larger than the limit", len(rec.circles))
+	}
+
+	var timeline bytes.Buffer
+
+	if err := rec.writeTimeline(&timeline); err != nil {
+		t.Fatal(err)
+	}
+
+	// Player 2 scores twice, so their
+	// score adds up over both turns.
+	expectedTimeline := `turn,player,points,score
+23,2,32,32
+46,1,63,63
+69,3,80,80
+92,2,107,139
+`
+
+	if got := timeline.String(); got != expectedTimeline {
+		t.Errorf("timeline is:\n%s\nexpected:\n%s", got, expectedTimeline)
+	}
+
+	expectedLeadChanges := []string{
+		"Turn 23: player 2 takes the lead",
+		"Turn 46: player 1 takes the lead from player 2",
+		"Turn 69: player 3 takes the lead from player 1",
+		"Turn 92: player 2 takes the lead from player 3",
+	}
+
+	if got := rec.describeLeadChanges(); !slices.Equal(got, expectedLeadChanges) {
+		t.Errorf("lead changes are %q, expected %q", got, expectedLeadChanges)
+	}
+}
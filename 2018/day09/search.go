@@ -0,0 +1,169 @@
+// search.go finds the smallest value of the last marble
+// for which the winning Elf's score reaches a target
+// score. Run it with, for example:
+//
+//	go run ./cmd/aoc tool 2018 9 search -players 10 8317 146373
+//
+// This finds the smallest last marble for both 8317 and
+// 146373, in a game with 10 players.
+
+package day09
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// checkpoint is the marble with which the
+// high score of a game became highScore.
+type checkpoint struct {
+	lastMarble int
+	highScore  int
+}
+
+// searcher finds the smallest value of the last marble
+// for which the high score of a game reaches a target.
+//
+// A score never decreases, so neither does the high
+// score. The high score after each marble is the same,
+// no matter what the last marble of the game is. So
+// instead of playing the game again for each value of
+// the last marble, we keep playing a single game one
+// marble at a time, until the high score reaches the
+// target.
+//
+// Each time the high score increases, we save a
+// checkpoint. A later search for a target that is
+// already reached, only needs to look up the first
+// checkpoint that reaches it. A search for a higher
+// target continues the game where we left off.
+type searcher struct {
+	circle *circle
+
+	// Checkpoints, in the order they were saved.
+	// Both lastMarble and highScore increase.
+	checkpoints []checkpoint
+
+	highScore int
+
+	// The largest last marble we try
+	limit int
+}
+
+// newSearcher creates a searcher for this game, where
+// lastMarble is ignored, that tries last marbles up to
+// and including limit.
+func newSearcher(g game, limit int) *searcher {
+	return &searcher{circle: newCircle(g, 0), limit: limit}
+}
+
+// smallestLastMarble returns the smallest value of the
+// last marble for which the high score of the game is
+// at least target. Like play, it stops once ctx is
+// done, and later searches continue where it
+// stopped.
+func (s *searcher) smallestLastMarble(ctx context.Context, target int) (int, error) {
+	if target <= 0 {
+		// Without placing any marble, each
+		// player has a score of 0.
+		return 0, nil
+	}
+
+	if target <= s.highScore {
+		// The target is already reached, so look up
+		// the first checkpoint that reaches it.
+		//
+		// See: https://golang.org/pkg/sort/#Search
+		i := sort.Search(len(s.checkpoints), func(i int) bool {
+			return s.checkpoints[i].highScore >= target
+		})
+
+		return s.checkpoints[i].lastMarble, nil
+	}
+
+	// Continue the game until the high score
+	// reaches target.
+	for s.circle.lastValue < s.limit {
+		if s.circle.lastValue%turnsPerCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		player, points := s.circle.turn()
+
+		if points != 0 && s.circle.players[player] > s.highScore {
+			s.highScore = s.circle.players[player]
+			s.checkpoints = append(s.checkpoints, checkpoint{lastMarble: s.circle.lastValue, highScore: s.highScore})
+
+			if s.highScore >= target {
+				return s.circle.lastValue, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no last marble up to %d reaches a high score of %d, the highest is %d", s.limit, target, s.highScore)
+}
+
+func init() {
+	aoc.RegisterTool(2018, 9, "search", searchTool)
+}
+
+// searchTool finds the smallest last marble for each
+// target score in args. The game is described by the
+// flags, so it does not read input.
+func searchTool(ctx context.Context, input io.Reader, args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("2018 day 9 tool search", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	numberOfPlayers := flags.Int("players", 0, "number of players")
+	limit := flags.Int("limit", 100000000, "largest value of the last marble to try")
+
+	// See part_one.go
+	scoringModulus := flags.Int("modulus", 23, "keep each marble that is a multiple of this number")
+	removalOffset := flags.Int("offset", 7, "remove the marble this many marbles counter-clockwise when keeping a marble")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	g := game{
+		numberOfPlayers: *numberOfPlayers,
+		scoringModulus:  *scoringModulus,
+		removalOffset:   *removalOffset}
+
+	if err := g.validate(); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("no target scores given")
+	}
+
+	s := newSearcher(g, *limit)
+
+	for _, arg := range flags.Args() {
+		target, err := strconv.Atoi(arg)
+
+		if err != nil {
+			return fmt.Errorf("invalid target score %q", arg)
+		}
+
+		lastMarble, err := s.smallestLastMarble(ctx, target)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(output, "With %d players, the winning Elf's score reaches %d when the last marble is worth %d points.\n", *numberOfPlayers, target, lastMarble)
+	}
+
+	return nil
+}
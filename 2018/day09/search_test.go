@@ -0,0 +1,73 @@
+package day09
+
+import (
+	"context"
+	"testing"
+)
+
+// highScoreWith plays this game with this
+// last marble and returns its high score.
+func highScoreWith(t *testing.T, g game, lastMarble int) int {
+	t.Helper()
+
+	g.lastMarble = lastMarble
+
+	scores, err := g.play(context.Background(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return highScore(scores)
+}
+
+// TestSmallestLastMarble validates the last marble the
+// searcher finds for the second example in the README,
+// both by continuing the game and by looking up a
+// checkpoint.
+func TestSmallestLastMarble(t *testing.T) {
+	g := game{numberOfPlayers: 10, scoringModulus: 23, removalOffset: 7}
+
+	s := newSearcher(g, 100000)
+
+	for _, target := range []int{8317, 4000} {
+		// After the search for 8317, the search for
+		// 4000 must look up a checkpoint instead of
+		// continuing the game.
+		played := s.circle.lastValue
+
+		lastMarble, err := s.smallestLastMarble(context.Background(), target)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if target == 4000 && s.circle.lastValue != played {
+			t.Errorf("searching for %d continued the game from marble %d to %d", target, played, s.circle.lastValue)
+		}
+
+		if lastMarble > 1618 {
+			t.Errorf("smallest last marble for %d is %d, expected at most 1618", target, lastMarble)
+		}
+
+		if score := highScoreWith(t, g, lastMarble); score < target {
+			t.Errorf("high score with last marble %d is %d, expected at least %d", lastMarble, score, target)
+		}
+
+		if score := highScoreWith(t, g, lastMarble-1); score >= target {
+			t.Errorf("high score with last marble %d is %d, expected less than %d", lastMarble-1, score, target)
+		}
+	}
+}
+
+// TestSmallestLastMarbleLimit validates that the
+// searcher gives up at its limit.
+func TestSmallestLastMarbleLimit(t *testing.T) {
+	g := game{numberOfPlayers: 10, scoringModulus: 23, removalOffset: 7}
+
+	_, err := newSearcher(g, 100).smallestLastMarble(context.Background(), 8317)
+
+	if expected := "no last marble up to 100 reaches a high score of 8317, the highest is 107"; err == nil || err.Error() != expected {
+		t.Errorf("smallestLastMarble returned error %v, expected %q", err, expected)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// The status of a part after running all parts
+const (
+	statusOK             = "ok"
+	statusFailed         = "failed"
+	statusTimeout        = "timeout"
+	statusNotImplemented = "not implemented"
+)
+
+// result is the outcome of running a part
+type result struct {
+	part aoc.Part
+
+	status   string
+	answer   string
+	duration time.Duration
+
+	// Why the part failed or timed out
+	err error
+}
+
+// runAllCommand runs all parts of all days, or of a
+// single year, and prints a summary table like:
+//
+//	Year  Day  Part  Answer  Time      Status
+//	2018  1    1     430     1.204ms   ok
+//	2018  10   1                       not implemented
+//
+// Each part runs with the default arguments, and what
+// it writes to its output is discarded.
+func runAllCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("run all", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	year := flags.Int("year", 0, "only run the parts of this year")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of parts to run at the same time")
+	timeout := flags.Duration("timeout", time.Minute, "time each part may take")
+
+	if err := flags.Parse(args); err != nil {
+		return usagef("run all: %v", err)
+	}
+
+	if flags.NArg() != 0 {
+		return usagef("run all: unexpected arguments %q", flags.Args())
+	}
+
+	if *workers < 1 {
+		return usagef("run all: need at least 1 worker, got %d", *workers)
+	}
+
+	if *timeout <= 0 {
+		return usagef("run all: the timeout must be positive, got %v", *timeout)
+	}
+
+	results := allParts(*year)
+
+	start := time.Now()
+
+	runParts(ctx, results, *workers, *timeout)
+
+	elapsed := time.Since(start)
+
+	printResults(results, stdout)
+
+	// Count the parts of each status
+	counts := make(map[string]int)
+
+	for _, r := range results {
+		counts[r.status]++
+	}
+
+	fmt.Fprintf(stdout, "\n%d solved, %d failed, %d timed out and %d not implemented, in %v.\n",
+		counts[statusOK], counts[statusFailed], counts[statusTimeout], counts[statusNotImplemented], elapsed.Round(time.Millisecond))
+
+	if failed := counts[statusFailed] + counts[statusTimeout]; failed != 0 {
+		return fmt.Errorf("run all: %d of %d implemented parts did not succeed", failed, failed+counts[statusOK])
+	}
+
+	return nil
+}
+
+// allParts returns a result for each part of each day
+// of this year, or of each year with a registered part
+// if year is 0. Parts that are registered still have to
+// run, the others are not implemented.
+func allParts(year int) []*result {
+	var years []int
+
+	if year != 0 {
+		years = []int{year}
+	} else {
+		seen := make(map[int]bool)
+
+		for _, p := range aoc.Parts() {
+			if !seen[p.Year] {
+				seen[p.Year] = true
+				years = append(years, p.Year)
+			}
+		}
+
+		sort.Ints(years)
+	}
+
+	var results []*result
+
+	for _, y := range years {
+		// Each year has a puzzle on each day from
+		// December 1 up to and including 25. The
+		// last day has only a single part.
+		for day := 1; day <= 25; day++ {
+			for part := 1; part <= 2; part++ {
+				if day == 25 && part == 2 {
+					continue
+				}
+
+				p, prs := aoc.Lookup(y, day, part)
+
+				if !prs {
+					p = aoc.Part{Year: y, Day: day, Part: part}
+
+					results = append(results, &result{part: p, status: statusNotImplemented})
+
+					continue
+				}
+
+				results = append(results, &result{part: p})
+			}
+		}
+	}
+
+	return results
+}
+
+// runParts runs each implemented part of results, with
+// at most workers parts at the same time. Each part may
+// take up to timeout.
+//
+// Each worker only writes to the results it takes from
+// the channel, so results need no further locking.
+func runParts(ctx context.Context, results []*result, workers int, timeout time.Duration) {
+	jobs := make(chan *result)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for r := range jobs {
+				runWithTimeout(ctx, r, timeout)
+			}
+		}()
+	}
+
+	for _, r := range results {
+		if r.status != statusNotImplemented {
+			jobs <- r
+		}
+	}
+
+	close(jobs)
+
+	wg.Wait()
+}
+
+// runWithTimeout runs the part of r with a context that
+// is done after timeout, and stores its outcome in r.
+//
+// Parts check their context in their long loops, and
+// return once it is done. We always wait for the part
+// to return, so a part that does not check it keeps
+// its worker busy, and there are never more parts
+// running than workers.
+func runWithTimeout(ctx context.Context, r *result, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	answer, err := runPartSafely(ctx, r.part)
+
+	r.duration = time.Since(start)
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		r.status = statusTimeout
+		r.err = &aoc.Error{Part: r.part, Err: fmt.Errorf("timed out after %v", timeout)}
+	case err != nil:
+		r.status = statusFailed
+		r.err = err
+	default:
+		r.status = statusOK
+		r.answer = answer
+	}
+}
+
+// runPartSafely solves part p with the input of its day
+// like runPart, and returns a panic of p as an error.
+// A panic in one part must not stop the other parts
+// from running.
+func runPartSafely(ctx context.Context, p aoc.Part) (answer string, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &aoc.Error{Part: p, Err: fmt.Errorf("panic: %v", v)}
+		}
+	}()
+
+	return runPart(ctx, p, aoc.InputPath(p.Year, p.Day), nil, io.Discard)
+}
+
+// printResults prints the summary table of results,
+// followed by the error of each part that did not
+// succeed.
+func printResults(results []*result, stdout io.Writer) {
+	// See: https://golang.org/pkg/text/tabwriter/
+	table := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "Year\tDay\tPart\tAnswer\tTime\tStatus")
+
+	for _, r := range results {
+		duration := ""
+
+		if r.status != statusNotImplemented {
+			duration = r.duration.Round(time.Microsecond).String()
+		}
+
+		// An answer never spans multiple lines in
+		// the table, even if a part returns one
+		// that does.
+		answer := strings.ReplaceAll(r.answer, "\n", " ")
+
+		fmt.Fprintf(table, "%d\t%d\t%d\t%s\t%s\t%s\n", r.part.Year, r.part.Day, r.part.Part, answer, duration, r.status)
+	}
+
+	table.Flush()
+
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintln(stdout, r.err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// testTimeout is the timeout of each part in these
+// tests. The slow parts take twice as long.
+const testTimeout = 50 * time.Millisecond
+
+// Number of slow parts that are running, and the
+// most that were running at the same time.
+var (
+	running     atomic.Int32
+	mostRunning atomic.Int32
+)
+
+// slowPart takes twice testTimeout and ignores its
+// context, like a part without long loops to check
+// it in.
+func slowPart(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	n := running.Add(1)
+
+	for {
+		most := mostRunning.Load()
+
+		if n <= most || mostRunning.CompareAndSwap(most, n) {
+			break
+		}
+	}
+
+	time.Sleep(2 * testTimeout)
+
+	running.Add(-1)
+
+	return "slow", nil
+}
+
+func init() {
+	// These tests use year 1, which
+	// has no puzzles of its own.
+	aoc.Register(1, 1, 1, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		text, err := io.ReadAll(input)
+
+		return strings.TrimSpace(string(text)), err
+	})
+
+	aoc.Register(1, 1, 2, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		return "", errors.New("no answer")
+	})
+
+	aoc.Register(1, 2, 1, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		panic("out of marbles")
+	})
+
+	// Like day 1 part two on input where no
+	// frequency is ever reached twice.
+	aoc.Register(1, 2, 2, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		<-ctx.Done()
+
+		return "", ctx.Err()
+	})
+
+	for day := 3; day <= 4; day++ {
+		for part := 1; part <= 2; part++ {
+			aoc.Register(1, day, part, slowPart)
+		}
+	}
+}
+
+// inTestYear changes the working directory to a
+// temporary directory with an input for each day
+// of year 1 that has parts, until the test ends.
+func inTestYear(t *testing.T) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	for day := 1; day <= 4; day++ {
+		path := filepath.Join(root, aoc.InputPath(1, day))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("answer %d\n", day)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+// TestRunParts validates the status of each part,
+// and that no more parts run at the same time than
+// there are workers, even after a part timed out.
+func TestRunParts(t *testing.T) {
+	inTestYear(t)
+
+	mostRunning.Store(0)
+
+	results := allParts(1)
+
+	if len(results) != 49 {
+		t.Fatalf("year 1 has %d parts, expected 49", len(results))
+	}
+
+	runParts(context.Background(), results, 2, testTimeout)
+
+	expected := []struct {
+		status string
+		answer string
+		err    string
+	}{
+		{statusOK, "answer 1", ""},
+		{statusFailed, "", "1 day 1 part 2: no answer"},
+		{statusFailed, "", "1 day 2 part 1: panic: out of marbles"},
+		{statusTimeout, "", "1 day 2 part 2: timed out after 50ms"},
+		{statusTimeout, "", "1 day 3 part 1: timed out after 50ms"},
+		{statusTimeout, "", "1 day 3 part 2: timed out after 50ms"},
+		{statusTimeout, "", "1 day 4 part 1: timed out after 50ms"},
+		{statusTimeout, "", "1 day 4 part 2: timed out after 50ms"},
+	}
+
+	for i, e := range expected {
+		r := results[i]
+
+		errText := ""
+
+		if r.err != nil {
+			errText = r.err.Error()
+		}
+
+		if r.status != e.status || r.answer != e.answer || errText != e.err {
+			t.Errorf("%v is %s with answer %q and error %q, expected %s with answer %q and error %q", r.part, r.status, r.answer, errText, e.status, e.answer, e.err)
+		}
+	}
+
+	for _, r := range results[8:] {
+		if r.status != statusNotImplemented {
+			t.Errorf("%v is %s, expected %s", r.part, r.status, statusNotImplemented)
+		}
+	}
+
+	if most := mostRunning.Load(); most > 2 {
+		t.Errorf("%d slow parts were running at the same time with 2 workers", most)
+	}
+
+	if n := running.Load(); n != 0 {
+		t.Errorf("%d slow parts are still running after runParts returned", n)
+	}
+}
+
+// TestRunAllCommand validates the table and summary
+// that run all prints.
+func TestRunAllCommand(t *testing.T) {
+	inTestYear(t)
+
+	var stdout strings.Builder
+
+	err := command(context.Background(), []string{"run", "all", "-year", "1", "-workers", "4", "-timeout", testTimeout.String()}, &stdout)
+
+	if err == nil || err.Error() != "run all: 7 of 8 implemented parts did not succeed" {
+		t.Errorf("error is %v", err)
+	}
+
+	lines := strings.Split(stdout.String(), "\n")
+
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "Year Day Part Answer Time Status" {
+		t.Errorf("header is %q", lines[0])
+	}
+
+	if fields := strings.Fields(lines[1]); len(fields) != 7 || fields[4] != "1" || fields[6] != statusOK {
+		t.Errorf("row of 1 day 1 part 1 is %q", lines[1])
+	}
+
+	if !strings.Contains(stdout.String(), "\n1 solved, 2 failed, 5 timed out and 41 not implemented, in ") {
+		t.Errorf("summary is missing from:\n%s", stdout.String())
+	}
+}
+
+// TestRunAllUsage validates that run all rejects
+// flags it cannot run with.
+func TestRunAllUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"run", "all", "-workers", "0"},
+		{"run", "all", "-timeout", "0s"},
+		{"run", "all", "-year", "x"},
+		{"run", "all", "2018"},
+	} {
+		err := command(context.Background(), args, io.Discard)
+
+		var usageErr *usageError
+
+		if !errors.As(err, &usageErr) {
+			t.Errorf("%q returns %v, expected a usage error", args, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Command aoc runs the solutions of the puzzles. Run it
+// from the root of the repository, for example:
+//
+//	go run ./cmd/aoc run 2018 7
+//	go run ./cmd/aoc run 2018 7 2 -workers 2 -base 0
+//	go run ./cmd/aoc run all -year 2018
+//	go run ./cmd/aoc tool 2018 6 render -png areas.png
+//
+// The first runs both parts of day 7 of 2018. The second
+// runs only part two, for a variant of the puzzle. The
+// third runs all parts of 2018 at the same time, and
+// prints a summary table. The last runs the tool
+// "render" of day 6.
+//
+// Solutions report errors to this command, instead of
+// exiting themselves. It exits with status 1 if any
+// part or tool fails, and with status 2 if it is used
+// incorrectly.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+const usage = `usage:
+  aoc run [-input path] <year> <day> [<part> [arguments...]]
+  aoc run all [-year year] [-workers n] [-timeout duration]
+  aoc tool [-input path] <year> <day> <tool> [arguments...]`
+
+// usageError is an error in how this
+// command is used.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message + "\n" + usage
+}
+
+// usagef formats a usageError
+func usagef(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	// Interrupting the command stops the parts
+	// and tools that are running, like a
+	// timeout does.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	err := command(ctx, os.Args[1:], os.Stdout)
+
+	stop()
+
+	if err == nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "aoc:", err)
+
+	var usageErr *usageError
+
+	if errors.As(err, &usageErr) {
+		os.Exit(2)
+	}
+
+	os.Exit(1)
+}
+
+// command runs the subcommand in args, and
+// writes everything it shows to stdout.
+func command(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usagef("no command given")
+	}
+
+	switch args[0] {
+	case "run":
+		return runCommand(ctx, args[1:], stdout)
+	case "tool":
+		return toolCommand(ctx, args[1:], stdout)
+	default:
+		return usagef("unknown command %q", args[0])
+	}
+}
+
+// parseDay parses the year and day from the start of
+// args, and returns them with the rest of args. It
+// also parses the -input flag before them, where
+// inputPath defaults to the input of the day.
+func parseDay(name string, args []string) (year int, day int, inputPath string, rest []string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	input := flags.String("input", "", "path of the puzzle input")
+
+	if err := flags.Parse(args); err != nil {
+		return 0, 0, "", nil, usagef("%v", err)
+	}
+
+	if flags.NArg() < 2 {
+		return 0, 0, "", nil, usagef("%s: expected a year and a day", name)
+	}
+
+	if year, err = strconv.Atoi(flags.Arg(0)); err != nil {
+		return 0, 0, "", nil, usagef("%s: invalid year %q", name, flags.Arg(0))
+	}
+
+	if day, err = strconv.Atoi(flags.Arg(1)); err != nil {
+		return 0, 0, "", nil, usagef("%s: invalid day %q", name, flags.Arg(1))
+	}
+
+	inputPath = *input
+
+	if inputPath == "" {
+		inputPath = aoc.InputPath(year, day)
+	}
+
+	return year, day, inputPath, flags.Args()[2:], nil
+}
+
+// runCommand runs one or both parts of a day, and
+// prints the answer of each part.
+func runCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) != 0 && args[0] == "all" {
+		return runAllCommand(ctx, args[1:], stdout)
+	}
+
+	year, day, inputPath, rest, err := parseDay("run", args)
+
+	if err != nil {
+		return err
+	}
+
+	var parts []aoc.Part
+
+	if len(rest) == 0 {
+		// Run each part of this day
+		for part := 1; part <= 2; part++ {
+			if p, prs := aoc.Lookup(year, day, part); prs {
+				parts = append(parts, p)
+			}
+		}
+
+		if len(parts) == 0 {
+			return usagef("run: %d day %d is not implemented", year, day)
+		}
+	} else {
+		part, err := strconv.Atoi(rest[0])
+
+		if err != nil {
+			return usagef("run: invalid part %q", rest[0])
+		}
+
+		p, prs := aoc.Lookup(year, day, part)
+
+		if !prs {
+			return usagef("run: %d day %d part %d is not implemented", year, day, part)
+		}
+
+		parts = append(parts, p)
+		rest = rest[1:]
+	}
+
+	for _, p := range parts {
+		answer, err := runPart(ctx, p, inputPath, rest, stdout)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "%v: %s\n", p, answer)
+	}
+
+	return nil
+}
+
+// withInput opens the puzzle input at inputPath, and
+// passes it to read. Parts and tools never open the
+// puzzle input themselves. Parts get it from here,
+// tools from a lazyInput.
+func withInput(inputPath string, read func(input io.Reader) error) error {
+	// Open the puzzle input for reading
+	inputFile, err := os.Open(inputPath)
+
+	// Opening file can have an error. If there is
+	// an error, it will be of type *PathError.
+	if err != nil {
+		return err
+	}
+
+	// Closes the file when we are done
+	defer inputFile.Close()
+
+	return read(inputFile)
+}
+
+// runPart solves part p with the input at inputPath,
+// and returns its answer.
+func runPart(ctx context.Context, p aoc.Part, inputPath string, args []string, output io.Writer) (string, error) {
+	var answer string
+
+	// Run already wraps its error in an *aoc.Error,
+	// so we keep it apart from the error of opening
+	// the input.
+	var runErr error
+
+	err := withInput(inputPath, func(input io.Reader) error {
+		answer, runErr = p.Run(ctx, input, args, output)
+
+		return nil
+	})
+
+	if err != nil {
+		return "", &aoc.Error{Part: p, Err: err}
+	}
+
+	return answer, runErr
+}
+
+// lazyInput is the puzzle input at path, which is only
+// opened on its first read. Some tools, like the search
+// tool of 2018 day 9, read no input at all. Those run
+// without an input file.
+type lazyInput struct {
+	path string
+
+	// The opened input, and the error
+	// of opening it, if any.
+	file *os.File
+	err  error
+}
+
+func (l *lazyInput) Read(p []byte) (int, error) {
+	if l.file == nil && l.err == nil {
+		l.file, l.err = os.Open(l.path)
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	return l.file.Read(p)
+}
+
+// Close closes the puzzle input, if it was opened
+func (l *lazyInput) Close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
+
+// toolCommand runs a tool of a day
+func toolCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	year, day, inputPath, rest, err := parseDay("tool", args)
+
+	if err != nil {
+		return err
+	}
+
+	names := aoc.ToolNames(year, day)
+
+	if len(rest) == 0 {
+		if len(names) == 0 {
+			return usagef("tool: %d day %d has no tools", year, day)
+		}
+
+		return usagef("tool: expected the name of a tool of %d day %d: %s", year, day, strings.Join(names, ", "))
+	}
+
+	tool, prs := aoc.LookupTool(year, day, rest[0])
+
+	if !prs {
+		return usagef("tool: %d day %d has no tool %q, it has: %s", year, day, rest[0], strings.Join(names, ", "))
+	}
+
+	// See lazyInput for why we do not open
+	// the input with withInput.
+	input := &lazyInput{path: inputPath}
+
+	// Closes the input when we are done
+	defer input.Close()
+
+	if err := tool(ctx, input, rest[1:], stdout); err != nil {
+		return fmt.Errorf("%d day %d tool %s: %w", year, day, rest[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TonnyGaric/adventofcode/internal/aoc"
+)
+
+// writeInput writes this puzzle input to a temporary
+// file, and returns its path.
+func writeInput(t *testing.T, input string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "input.txt")
+
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// TestRun validates that run prints the answer of
+// each part, or of the part that is given.
+func TestRun(t *testing.T) {
+	path := writeInput(t, "+1\n-2\n+3\n")
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"run", "-input", path, "2018", "1"}, []string{"2018 day 1 part 1: 2", "2018 day 1 part 2: 1"}},
+		{[]string{"run", "-input", path, "2018", "1", "2"}, []string{"2018 day 1 part 2: 1"}},
+	}
+
+	for _, test := range tests {
+		var stdout strings.Builder
+
+		if err := command(context.Background(), test.args, &stdout); err != nil {
+			t.Errorf("%q: %v", test.args, err)
+
+			continue
+		}
+
+		// Only the answers, not what
+		// the parts show.
+		var answers []string
+
+		for _, line := range strings.Split(stdout.String(), "\n") {
+			if strings.HasPrefix(line, "2018 day") {
+				answers = append(answers, line)
+			}
+		}
+
+		if strings.Join(answers, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("%q prints answers %q, expected %q", test.args, answers, test.expected)
+		}
+	}
+}
+
+// TestUsageErrors validates that using the command
+// incorrectly returns a usageError.
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"walk"},
+		{"run"},
+		{"run", "2018"},
+		{"run", "-x", "2018", "1"},
+		{"run", "twenty", "1"},
+		{"run", "2018", "one"},
+		{"run", "2018", "26"},
+		{"run", "2018", "1", "3"},
+		{"tool", "2018", "1"},
+		{"tool", "2018", "7"},
+		{"tool", "2018", "7", "draw"},
+	} {
+		err := command(context.Background(), args, &strings.Builder{})
+
+		var usageErr *usageError
+
+		if !errors.As(err, &usageErr) {
+			t.Errorf("%q returns %v, expected a usage error", args, err)
+		}
+	}
+}
+
+// TestPartErrors validates that an error of a part
+// says which part it is about, and is not a
+// usageError.
+func TestPartErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"run", "-input", writeInput(t, "+1\nx\n"), "2018", "1", "1"}, `2018 day 1 part 1: line 2: invalid number "x"`},
+		{[]string{"run", "-input", "missing.txt", "2018", "1", "1"}, "2018 day 1 part 1: open missing.txt: no such file or directory"},
+		{[]string{"run", "-input", writeInput(t, "+1\n"), "2018", "1", "1", "-x"}, `2018 day 1 part 1: unexpected arguments ["-x"]`},
+	}
+
+	for _, test := range tests {
+		err := command(context.Background(), test.args, &strings.Builder{})
+
+		var partErr *aoc.Error
+
+		if !errors.As(err, &partErr) || err.Error() != test.expected {
+			t.Errorf("%q returns %v, expected %q", test.args, err, test.expected)
+		}
+	}
+}
+
+// TestTool validates that tool passes the puzzle
+// input and the arguments to the tool.
+func TestTool(t *testing.T) {
+	path := writeInput(t, "Step C must be finished before step A can begin.\n")
+
+	var stdout strings.Builder
+
+	if err := command(context.Background(), []string{"tool", "-input", path, "2018", "7", "analyze", "-format", "dot"}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stdout.String(), `"C" -> "A";`) {
+		t.Errorf("tool prints %q, expected the edge from C to A", stdout.String())
+	}
+
+	err := command(context.Background(), []string{"tool", "-input", path, "2018", "7", "analyze", "-format", "svg"}, &stdout)
+
+	if err == nil || !strings.HasPrefix(err.Error(), "2018 day 7 tool analyze: ") {
+		t.Errorf("error is %v, expected one of the tool", err)
+	}
+
+	// The search tool reads no input, so it
+	// runs without an input file.
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+
+	stdout.Reset()
+
+	if err := command(context.Background(), []string{"tool", "-input", missing, "2018", "9", "search", "-players", "9", "32"}, &stdout); err != nil {
+		t.Errorf("search without an input file: %v", err)
+	}
+
+	// A tool that does read input, still
+	// reports that it is missing.
+	err = command(context.Background(), []string{"tool", "-input", missing, "2018", "7", "analyze"}, &stdout)
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("error is %v, expected that the input does not exist", err)
+	}
+}
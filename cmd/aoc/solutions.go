@@ -0,0 +1,15 @@
+package main
+
+// Importing the package of a day registers its
+// parts and tools, see package aoc.
+import (
+	_ "github.com/TonnyGaric/adventofcode/2018/day01"
+	_ "github.com/TonnyGaric/adventofcode/2018/day02"
+	_ "github.com/TonnyGaric/adventofcode/2018/day03"
+	_ "github.com/TonnyGaric/adventofcode/2018/day04"
+	_ "github.com/TonnyGaric/adventofcode/2018/day05"
+	_ "github.com/TonnyGaric/adventofcode/2018/day06"
+	_ "github.com/TonnyGaric/adventofcode/2018/day07"
+	_ "github.com/TonnyGaric/adventofcode/2018/day08"
+	_ "github.com/TonnyGaric/adventofcode/2018/day09"
+)
@@ -0,0 +1,3 @@
+module github.com/TonnyGaric/adventofcode
+
+go 1.23
@@ -0,0 +1,204 @@
+// Package aoc keeps track of all solutions, so a single
+// command can run any of them.
+//
+// Each day registers its parts, and any extra tools it
+// has, from an init function:
+//
+//	func init() {
+//		aoc.Register(2018, 1, 1, partOne)
+//	}
+//
+// Solutions never exit the program themselves. They
+// return an error instead, and the runner decides how
+// to report it.
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Solver solves a part of a puzzle, and returns the
+// answer. It reads the puzzle input from input. Some
+// parts can solve variants of the puzzle, which are
+// selected with flags in args. When args is empty, a
+// solver must solve the puzzle as described in its
+// README. Anything else a solver wants to show, like
+// how it got to the answer, is written to output.
+//
+// Parts that can take long, check ctx in their long
+// loops, and return its error once it is done, so
+// the runner can stop them.
+type Solver func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error)
+
+// Tool is an extra program of a day, like drawing the
+// input or analyzing how a part is solved. Like a
+// Solver, it reads the puzzle input from input, takes
+// its flags from args and should stop once ctx is
+// done. The runner only opens the input file once the
+// tool reads from input, so a tool that needs no input
+// runs without one.
+type Tool func(ctx context.Context, input io.Reader, args []string, output io.Writer) error
+
+// Part is a part of the puzzle of a day
+type Part struct {
+	Year int
+	Day  int
+	Part int
+
+	Solve Solver
+}
+
+// String returns the name of part p, for
+// example "2018 day 7 part 2".
+func (p Part) String() string {
+	return fmt.Sprintf("%d day %d part %d", p.Year, p.Day, p.Part)
+}
+
+// key identifies a day
+type key struct {
+	year int
+	day  int
+}
+
+var (
+	parts = make(map[key]map[int]Part)
+	tools = make(map[key]map[string]Tool)
+)
+
+// Register registers solve as the solver of this part.
+// It panics if this part is already registered, because
+// that can only be a mistake in the code.
+func Register(year int, day int, part int, solve Solver) {
+	k := key{year: year, day: day}
+
+	if parts[k] == nil {
+		parts[k] = make(map[int]Part)
+	}
+
+	if _, prs := parts[k][part]; prs {
+		panic(fmt.Sprintf("aoc: %d day %d part %d is registered twice", year, day, part))
+	}
+
+	parts[k][part] = Part{Year: year, Day: day, Part: part, Solve: solve}
+}
+
+// RegisterTool registers tool with this name for this
+// day. It panics if the day already has a tool with
+// this name.
+func RegisterTool(year int, day int, name string, tool Tool) {
+	k := key{year: year, day: day}
+
+	if tools[k] == nil {
+		tools[k] = make(map[string]Tool)
+	}
+
+	if _, prs := tools[k][name]; prs {
+		panic(fmt.Sprintf("aoc: %d day %d has two tools named %q", year, day, name))
+	}
+
+	tools[k][name] = tool
+}
+
+// Parts returns all registered parts, ordered by
+// year, day and part.
+func Parts() []Part {
+	var all []Part
+
+	for _, partsOfDay := range parts {
+		for _, p := range partsOfDay {
+			all = append(all, p)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+
+		return a.Part < b.Part
+	})
+
+	return all
+}
+
+// Lookup returns the registered part of this day,
+// and whether it is registered.
+func Lookup(year int, day int, part int) (Part, bool) {
+	p, prs := parts[key{year: year, day: day}][part]
+
+	return p, prs
+}
+
+// LookupTool returns the registered tool with this name
+// of this day, and whether it is registered.
+func LookupTool(year int, day int, name string) (Tool, bool) {
+	tool, prs := tools[key{year: year, day: day}][name]
+
+	return tool, prs
+}
+
+// ToolNames returns the names of all tools
+// of this day, in alphabetical order.
+func ToolNames(year int, day int) []string {
+	var names []string
+
+	for name := range tools[key{year: year, day: day}] {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NoArgs returns an error if args is not empty, for
+// solvers that do not solve any variants.
+func NoArgs(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+
+	return nil
+}
+
+// InputPath returns the path of the puzzle input of
+// this day, relative to the root of the repository.
+func InputPath(year int, day int) string {
+	return fmt.Sprintf("%d/day%02d/input.txt", year, day)
+}
+
+// Error is an error of a part, which tells which part
+// it is about.
+type Error struct {
+	Part Part
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Part, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run solves part p with this input and these args,
+// and wraps any error in an *Error.
+func (p Part) Run(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+	answer, err := p.Solve(ctx, input, args, output)
+
+	if err != nil {
+		return "", &Error{Part: p, Err: err}
+	}
+
+	return answer, nil
+}
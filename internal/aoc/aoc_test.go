@@ -0,0 +1,128 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// errTest is the error of the part registered
+// as 1 day 1 part 2 below.
+var errTest = errors.New("test error")
+
+func init() {
+	// These tests use year 1, which
+	// has no puzzles of its own.
+	Register(1, 2, 1, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		text, err := io.ReadAll(input)
+
+		return strings.ToUpper(string(text)), err
+	})
+
+	Register(1, 1, 2, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		return "", errTest
+	})
+
+	Register(1, 1, 1, func(ctx context.Context, input io.Reader, args []string, output io.Writer) (string, error) {
+		return "", NoArgs(args)
+	})
+
+	RegisterTool(1, 1, "b", func(ctx context.Context, input io.Reader, args []string, output io.Writer) error {
+		return nil
+	})
+
+	RegisterTool(1, 1, "a", func(ctx context.Context, input io.Reader, args []string, output io.Writer) error {
+		return nil
+	})
+}
+
+// TestParts validates that Parts orders the
+// parts by year, day and part.
+func TestParts(t *testing.T) {
+	var names []string
+
+	for _, p := range Parts() {
+		names = append(names, p.String())
+	}
+
+	expected := []string{"1 day 1 part 1", "1 day 1 part 2", "1 day 2 part 1"}
+
+	if !slices.Equal(names, expected) {
+		t.Errorf("parts are %q, expected %q", names, expected)
+	}
+}
+
+// TestRun validates that Run passes input to the part,
+// and wraps its error in an *Error.
+func TestRun(t *testing.T) {
+	p, prs := Lookup(1, 2, 1)
+
+	if !prs {
+		t.Fatal("1 day 2 part 1 is not registered")
+	}
+
+	if answer, err := p.Run(context.Background(), strings.NewReader("abc"), nil, io.Discard); err != nil || answer != "ABC" {
+		t.Errorf("answer is %q with error %v, expected \"ABC\"", answer, err)
+	}
+
+	p, _ = Lookup(1, 1, 2)
+
+	_, err := p.Run(context.Background(), strings.NewReader(""), nil, io.Discard)
+
+	var partErr *Error
+
+	if !errors.As(err, &partErr) || partErr.Part.Part != 2 || !errors.Is(err, errTest) {
+		t.Errorf("error is %#v, expected an *Error of part 2 that wraps errTest", err)
+	}
+
+	if expected := "1 day 1 part 2: test error"; err.Error() != expected {
+		t.Errorf("error is %q, expected %q", err, expected)
+	}
+
+	p, _ = Lookup(1, 1, 1)
+
+	if _, err := p.Run(context.Background(), strings.NewReader(""), []string{"-x"}, io.Discard); err == nil {
+		t.Error("NoArgs accepted an argument")
+	}
+}
+
+// TestLookup validates looking up parts and
+// tools that are not registered.
+func TestLookup(t *testing.T) {
+	if _, prs := Lookup(1, 2, 2); prs {
+		t.Error("1 day 2 part 2 is registered")
+	}
+
+	if _, prs := LookupTool(1, 1, "c"); prs {
+		t.Error("tool c of 1 day 1 is registered")
+	}
+
+	if _, prs := LookupTool(1, 1, "a"); !prs {
+		t.Error("tool a of 1 day 1 is not registered")
+	}
+
+	if names := ToolNames(1, 1); !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("tools of 1 day 1 are %q, expected [a b]", names)
+	}
+}
+
+// TestRegisterTwice validates that registering
+// a part twice panics.
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering 1 day 1 part 1 twice did not panic")
+		}
+	}()
+
+	Register(1, 1, 1, nil)
+}
+
+func TestInputPath(t *testing.T) {
+	if path := InputPath(2018, 7); path != "2018/day07/input.txt" {
+		t.Errorf("path is %q, expected \"2018/day07/input.txt\"", path)
+	}
+}
@@ -0,0 +1,238 @@
+// Package grid provides a two-dimensional grid of values,
+// for puzzles that take place on locations with integer
+// x and y coordinates.
+//
+// Like in the puzzles, x increases to the right and y
+// increases downwards, so 0,0 is at the top left.
+package grid
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Point is a location on a grid
+type Point struct {
+	X int
+	Y int
+}
+
+// Add returns the point p moved by q
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// ManhattanDistance calculates the Manhattan
+// distance between point a and b.
+//
+// See: https://en.wikipedia.org/wiki/Taxicab_geometry
+func ManhattanDistance(a Point, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// abs returns the absolute value of i
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// Orthogonal holds the 4 directions to the neighbors
+// of a point that share a side with it: up, right,
+// down and left.
+var Orthogonal = []Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}}
+
+// AllDirections holds the 8 directions to the neighbors
+// of a point that share a side or a corner with it,
+// clockwise starting at up.
+var AllDirections = []Point{
+	{X: 0, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 0}, {X: 1, Y: 1},
+	{X: 0, Y: 1}, {X: -1, Y: 1}, {X: -1, Y: 0}, {X: -1, Y: -1}}
+
+// Grid holds a value of type T for each location from
+// its top left up to and including its bottom right
+// corner.
+//
+// The values are stored row by row in a single slice.
+// A sub-grid from Sub shares this slice with the grid
+// it was taken from, so stride is the number of values
+// in a row of the original grid.
+type Grid[T any] struct {
+	topLeft     Point
+	bottomRight Point
+
+	values []T
+
+	// Index in values of topLeft
+	offset int
+
+	stride int
+}
+
+// New creates a grid from topLeft up to and including
+// bottomRight, where each value is the zero value of T.
+func New[T any](topLeft Point, bottomRight Point) *Grid[T] {
+	width := max(bottomRight.X-topLeft.X+1, 0)
+	height := max(bottomRight.Y-topLeft.Y+1, 0)
+
+	return &Grid[T]{
+		topLeft:     topLeft,
+		bottomRight: bottomRight,
+		values:      make([]T, width*height),
+		stride:      width}
+}
+
+// Bounds returns the top left and bottom right
+// corner of the grid.
+func (g *Grid[T]) Bounds() (Point, Point) {
+	return g.topLeft, g.bottomRight
+}
+
+// Width returns the number of columns of the grid
+func (g *Grid[T]) Width() int {
+	return max(g.bottomRight.X-g.topLeft.X+1, 0)
+}
+
+// Height returns the number of rows of the grid
+func (g *Grid[T]) Height() int {
+	return max(g.bottomRight.Y-g.topLeft.Y+1, 0)
+}
+
+// Contains validates if point p is within the grid
+func (g *Grid[T]) Contains(p Point) bool {
+	return p.X >= g.topLeft.X && p.X <= g.bottomRight.X &&
+		p.Y >= g.topLeft.Y && p.Y <= g.bottomRight.Y
+}
+
+// index returns the index in values of point p
+func (g *Grid[T]) index(p Point) int {
+	return g.offset + (p.Y-g.topLeft.Y)*g.stride + p.X - g.topLeft.X
+}
+
+// Get returns the value at point p. Point p
+// must be within the grid.
+func (g *Grid[T]) Get(p Point) T {
+	return g.values[g.index(p)]
+}
+
+// Set changes the value at point p to value.
+// Point p must be within the grid.
+func (g *Grid[T]) Set(p Point, value T) {
+	g.values[g.index(p)] = value
+}
+
+// Fill changes each value of the grid to value
+func (g *Grid[T]) Fill(value T) {
+	for p := range g.All() {
+		g.Set(p, value)
+	}
+}
+
+// Neighbors returns the neighbors of point p in
+// these directions, that are within the grid.
+// Use Orthogonal or AllDirections for the
+// directions.
+func (g *Grid[T]) Neighbors(p Point, directions []Point) []Point {
+	neighbors := make([]Point, 0, len(directions))
+
+	for _, direction := range directions {
+		if neighbor := p.Add(direction); g.Contains(neighbor) {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
+
+// All iterates over each point of the grid and its
+// value, row by row from the top left, for example:
+//
+//	for p, value := range g.All() {
+//		...
+//	}
+//
+// See: https://go.dev/ref/spec#For_range
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for y := g.topLeft.Y; y <= g.bottomRight.Y; y++ {
+			for x := g.topLeft.X; x <= g.bottomRight.X; x++ {
+				p := Point{X: x, Y: y}
+
+				if !yield(p, g.Get(p)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Sub returns a view on the part of the grid from topLeft
+// up to and including bottomRight, clipped to the grid.
+//
+// The view shares its values with the grid, so changing
+// a value of one changes the same value of the other.
+func (g *Grid[T]) Sub(topLeft Point, bottomRight Point) *Grid[T] {
+	topLeft = Point{X: max(topLeft.X, g.topLeft.X), Y: max(topLeft.Y, g.topLeft.Y)}
+	bottomRight = Point{X: min(bottomRight.X, g.bottomRight.X), Y: min(bottomRight.Y, g.bottomRight.Y)}
+
+	sub := &Grid[T]{topLeft: topLeft, bottomRight: bottomRight, values: g.values, stride: g.stride}
+
+	if sub.Width() > 0 && sub.Height() > 0 {
+		sub.offset = g.index(topLeft)
+	}
+
+	return sub
+}
+
+// Text draws the grid with a character for each location,
+// as returned by char, and a line for each row. This is
+// how the puzzles draw their grids, for example:
+//
+//	..........
+//	.A........
+//	..........
+func (g *Grid[T]) Text(char func(p Point, value T) rune) string {
+	var builder strings.Builder
+
+	for p, value := range g.All() {
+		builder.WriteRune(char(p, value))
+
+		if p.X == g.bottomRight.X {
+			builder.WriteRune('\n')
+		}
+	}
+
+	return builder.String()
+}
+
+// Image draws the grid as an image, where each location
+// is a square of scale by scale pixels, in the color as
+// returned by colorOf.
+func (g *Grid[T]) Image(scale int, colorOf func(p Point, value T) color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, g.Width()*scale, g.Height()*scale))
+
+	for p, value := range g.All() {
+		c := colorOf(p, value)
+
+		// Fill the square of this location
+		for dy := 0; dy < scale; dy++ {
+			for dx := 0; dx < scale; dx++ {
+				img.Set((p.X-g.topLeft.X)*scale+dx, (p.Y-g.topLeft.Y)*scale+dy, c)
+			}
+		}
+	}
+
+	return img
+}
+
+// EncodePNG draws the grid like Image, and
+// writes it to writer in PNG format.
+func (g *Grid[T]) EncodePNG(writer io.Writer, scale int, colorOf func(p Point, value T) color.Color) error {
+	return png.Encode(writer, g.Image(scale, colorOf))
+}
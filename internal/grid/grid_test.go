@@ -0,0 +1,181 @@
+package grid
+
+import (
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// numbered creates a grid from topLeft up to and
+// including bottomRight, where the value of each
+// point is 10 times its x plus its y.
+func numbered(topLeft Point, bottomRight Point) *Grid[int] {
+	g := New[int](topLeft, bottomRight)
+
+	for p := range g.All() {
+		g.Set(p, p.X*10+p.Y)
+	}
+
+	return g
+}
+
+func TestManhattanDistance(t *testing.T) {
+	if d := ManhattanDistance(Point{X: 1, Y: -2}, Point{X: -3, Y: 4}); d != 10 {
+		t.Errorf("distance is %d, expected 10", d)
+	}
+}
+
+// TestSub validates that a sub-grid of a grid that
+// does not start at 0,0 shares its values with that
+// grid, also when taking a sub-grid of a sub-grid.
+func TestSub(t *testing.T) {
+	g := numbered(Point{X: -2, Y: -1}, Point{X: 3, Y: 2})
+
+	sub := g.Sub(Point{X: 0, Y: 0}, Point{X: 2, Y: 1})
+
+	if sub.Width() != 3 || sub.Height() != 2 {
+		t.Fatalf("sub-grid is %d by %d, expected 3 by 2", sub.Width(), sub.Height())
+	}
+
+	for p, value := range sub.All() {
+		if value != g.Get(p) {
+			t.Errorf("value of %v is %d in the sub-grid, expected %d", p, value, g.Get(p))
+		}
+	}
+
+	// The offset and stride of the sub-grid
+	// must carry over to its own sub-grid.
+	subSub := sub.Sub(Point{X: 1, Y: 1}, Point{X: 2, Y: 1})
+
+	subSub.Set(Point{X: 2, Y: 1}, -1)
+
+	if g.Get(Point{X: 2, Y: 1}) != -1 || sub.Get(Point{X: 2, Y: 1}) != -1 {
+		t.Error("changing a value of a sub-grid does not change the grid")
+	}
+
+	if g.Get(Point{X: 3, Y: 1}) != 31 || g.Get(Point{X: 2, Y: 2}) != 22 {
+		t.Error("changing a value of a sub-grid changes another value of the grid")
+	}
+}
+
+// TestSubClipping validates that Sub clips the
+// sub-grid to the grid.
+func TestSubClipping(t *testing.T) {
+	g := numbered(Point{X: -2, Y: -1}, Point{X: 3, Y: 2})
+
+	sub := g.Sub(Point{X: -5, Y: 1}, Point{X: 0, Y: 9})
+
+	topLeft, bottomRight := sub.Bounds()
+
+	if topLeft != (Point{X: -2, Y: 1}) || bottomRight != (Point{X: 0, Y: 2}) {
+		t.Errorf("sub-grid is from %v to %v, expected from {-2 1} to {0 2}", topLeft, bottomRight)
+	}
+
+	if value := sub.Get(Point{X: -2, Y: 1}); value != -19 {
+		t.Errorf("value of {-2 1} is %d, expected -19", value)
+	}
+
+	outside := g.Sub(Point{X: 10, Y: 10}, Point{X: 20, Y: 20})
+
+	if outside.Width() != 0 || outside.Height() != 0 {
+		t.Errorf("sub-grid outside the grid is %d by %d, expected 0 by 0", outside.Width(), outside.Height())
+	}
+
+	for p := range outside.All() {
+		t.Errorf("sub-grid outside the grid contains %v", p)
+	}
+}
+
+// TestNeighbors validates that Neighbors only returns
+// the neighbors within the grid.
+func TestNeighbors(t *testing.T) {
+	g := New[int](Point{X: 0, Y: 0}, Point{X: 2, Y: 2})
+
+	tests := []struct {
+		p          Point
+		directions []Point
+		expected   []Point
+	}{
+		{Point{X: 0, Y: 0}, Orthogonal, []Point{{X: 1, Y: 0}, {X: 0, Y: 1}}},
+		{Point{X: 2, Y: 1}, Orthogonal, []Point{{X: 2, Y: 0}, {X: 2, Y: 2}, {X: 1, Y: 1}}},
+		{Point{X: 1, Y: 1}, Orthogonal, []Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 1}}},
+		{Point{X: 2, Y: 2}, AllDirections, []Point{{X: 2, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 1}}},
+		{Point{X: 1, Y: 1}, AllDirections, []Point{
+			{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0},
+			{X: 0, Y: 1}, {X: 2, Y: 1},
+			{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}},
+	}
+
+	for _, test := range tests {
+		neighbors := g.Neighbors(test.p, test.directions)
+
+		slices.SortFunc(neighbors, comparePoints)
+		slices.SortFunc(test.expected, comparePoints)
+
+		if !slices.Equal(neighbors, test.expected) {
+			t.Errorf("neighbors of %v are %v, expected %v", test.p, neighbors, test.expected)
+		}
+	}
+}
+
+// comparePoints orders points row by row
+func comparePoints(a Point, b Point) int {
+	if a.Y != b.Y {
+		return a.Y - b.Y
+	}
+
+	return a.X - b.X
+}
+
+// TestText validates that Text draws each row of
+// the grid, and of a sub-grid, on its own line.
+func TestText(t *testing.T) {
+	g := New[bool](Point{X: 0, Y: 0}, Point{X: 3, Y: 2})
+
+	g.Set(Point{X: 1, Y: 1}, true)
+	g.Set(Point{X: 3, Y: 2}, true)
+
+	char := func(p Point, value bool) rune {
+		if value {
+			return '#'
+		}
+
+		return '.'
+	}
+
+	if text := g.Text(char); text != "....\n.#..\n...#\n" {
+		t.Errorf("grid is drawn as %q", text)
+	}
+
+	if text := g.Sub(Point{X: 1, Y: 1}, Point{X: 3, Y: 5}).Text(char); text != "#..\n..#\n" {
+		t.Errorf("sub-grid is drawn as %q", text)
+	}
+}
+
+// TestImage validates that Image draws each point
+// as a square of scale by scale pixels.
+func TestImage(t *testing.T) {
+	g := New[bool](Point{X: 5, Y: 5}, Point{X: 6, Y: 5})
+
+	g.Set(Point{X: 6, Y: 5}, true)
+
+	img := g.Image(3, func(p Point, value bool) color.Color {
+		if value {
+			return color.White
+		}
+
+		return color.Black
+	})
+
+	if bounds := img.Bounds(); bounds.Dx() != 6 || bounds.Dy() != 3 {
+		t.Fatalf("image is %d by %d pixels, expected 6 by 3", bounds.Dx(), bounds.Dy())
+	}
+
+	if r, _, _, _ := img.At(2, 2).RGBA(); r != 0 {
+		t.Error("pixel 2, 2 is not black")
+	}
+
+	if r, _, _, _ := img.At(3, 0).RGBA(); r != 0xffff {
+		t.Error("pixel 3, 0 is not white")
+	}
+}
@@ -0,0 +1,347 @@
+// Package parse provides helpers to parse puzzle
+// input, so each day can focus on what its lines
+// mean instead of how to read them.
+//
+// All errors about the content of the input contain
+// the number of the line they are about, so a bad
+// input can be fixed without searching for it.
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxLineLength is the length of the longest line a
+// Scanner can read. Some puzzles have their whole
+// input on a single line, which can be much longer
+// than the default of bufio.Scanner.
+const maxLineLength = 64 * 1024 * 1024
+
+// Line is a line of input
+type Line struct {
+	// Number of the line, where the
+	// first line is number 1.
+	Number int
+
+	// Text of the line, without the line ending and
+	// without leading and trailing whitespace.
+	Text string
+}
+
+// Errorf formats an error about this line, which
+// starts with the number of the line, for example:
+//
+//	line 3: expected a number, got "x"
+func (l Line) Errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %w", l.Number, fmt.Errorf(format, args...))
+}
+
+// Int parses the text of this line as a single
+// integer, like "+7" or "-3".
+func (l Line) Int() (int, error) {
+	return l.Atoi(l.Text, "number")
+}
+
+// Atoi parses s, which is part of this line, as an
+// integer. If s is not an integer, the error names
+// what s was expected to be.
+func (l Line) Atoi(s string, what string) (int, error) {
+	i, err := strconv.Atoi(s)
+
+	if err != nil {
+		return 0, l.Errorf("invalid %s %q", what, s)
+	}
+
+	return i, nil
+}
+
+// Scanner reads the lines of input that are not blank,
+// like bufio.Scanner. Line endings can be either "\n"
+// or "\r\n".
+type Scanner struct {
+	scanner *bufio.Scanner
+
+	line Line
+
+	// Number of the last line that was read,
+	// including blank lines.
+	number int
+}
+
+// NewScanner creates a Scanner to read from reader
+func NewScanner(reader io.Reader) *Scanner {
+	// Note that the split function of bufio.Scanner
+	// defaults to ScanLines—which is each line of
+	// text, without "\n" or "\r\n".
+	scanner := bufio.NewScanner(reader)
+
+	scanner.Buffer(nil, maxLineLength)
+
+	return &Scanner{scanner: scanner}
+}
+
+// Scan advances the Scanner to the next line that is
+// not blank, which is then available through Line.
+// It returns false when there are no more lines, or
+// when reading failed. Err returns why reading
+// failed.
+func (s *Scanner) Scan() bool {
+	for s.scanner.Scan() {
+		s.number++
+
+		if text := strings.TrimSpace(s.scanner.Text()); text != "" {
+			s.line = Line{Number: s.number, Text: text}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Line returns the line read by the last call to Scan
+func (s *Scanner) Line() Line {
+	return s.line
+}
+
+// Err returns the first error that occurred while
+// reading, or nil if there was none.
+func (s *Scanner) Err() error {
+	return s.scanner.Err()
+}
+
+// Lines reads all lines that are not blank from reader
+func Lines(reader io.Reader) ([]Line, error) {
+	return Map(reader, func(line Line) (Line, error) {
+		return line, nil
+	})
+}
+
+// Map reads all lines that are not blank from reader,
+// and parses each of them with parse. It stops at the
+// first error of parse, which should come from Errorf
+// of the line, so it contains the number of the line.
+func Map[T any](reader io.Reader, parse func(line Line) (T, error)) ([]T, error) {
+	var values []T
+
+	scanner := NewScanner(reader)
+
+	for scanner.Scan() {
+		value, err := parse(scanner.Line())
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Ints extracts all integers from this line, in the
+// order they appear. Anything between the integers is
+// ignored, so a line like "#13 @ 176,605: 24x11" gives
+// 13, 176, 605, 24 and 11.
+//
+// A "-" directly before an integer makes it negative,
+// unless the "-" directly follows a digit. So "x=-3"
+// gives -3, while a date like "1518-04-09" gives
+// 1518, 4 and 9.
+func Ints(line Line) ([]int, error) {
+	var ints []int
+
+	s := line.Text
+
+	for i := 0; i < len(s); {
+		if !isDigit(s[i]) {
+			i++
+
+			continue
+		}
+
+		start := i
+
+		if start > 0 && s[start-1] == '-' && (start < 2 || !isDigit(s[start-2])) {
+			start--
+		}
+
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+
+		// This only fails if the integer is too large
+		n, err := line.Atoi(s[start:i], "number")
+
+		if err != nil {
+			return nil, err
+		}
+
+		ints = append(ints, n)
+	}
+
+	return ints, nil
+}
+
+// isDigit validates if b is one of the digits 0 to 9
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// Pattern is a regular expression that each line of
+// some input must match. It remembers an example of
+// a line that matches, so errors can show what a
+// line should look like.
+//
+// See: https://golang.org/pkg/regexp/syntax/
+type Pattern struct {
+	regexp  *regexp.Regexp
+	example string
+}
+
+// MustCompile creates a Pattern from this regular
+// expression, which must match the whole line. It
+// panics if expression is invalid, or if example
+// does not match it.
+func MustCompile(expression string, example string) *Pattern {
+	p := &Pattern{regexp: regexp.MustCompile(expression), example: example}
+
+	if !p.regexp.MatchString(example) {
+		panic(fmt.Sprintf("parse: example %q does not match %q", example, expression))
+	}
+
+	return p
+}
+
+// Match matches line against the pattern, and returns
+// the text of each group in the pattern. A group that
+// did not take part in the match is "".
+func (p *Pattern) Match(line Line) ([]string, error) {
+	matches := p.regexp.FindStringSubmatch(line.Text)
+
+	if matches == nil || matches[0] != line.Text {
+		return nil, line.Errorf("expected a line like %q, got %q", p.example, line.Text)
+	}
+
+	return matches[1:], nil
+}
+
+// Ints matches line against the pattern like Match, and
+// parses the text of each group as an integer.
+func (p *Pattern) Ints(line Line) ([]int, error) {
+	groups, err := p.Match(line)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ints := make([]int, len(groups))
+
+	for i, group := range groups {
+		if ints[i], err = line.Atoi(group, fmt.Sprintf("number in group %d", i+1)); err != nil {
+			return nil, err
+		}
+	}
+
+	return ints, nil
+}
+
+// IntFields reads integers separated by any amount of
+// whitespace, including newlines, from reader. Unlike
+// Lines, it reads one byte at a time and only keeps
+// the integer it is reading, so the input does not
+// need to fit in memory, even if it is a single huge
+// line.
+//
+// An invalid integer is reported with its line and
+// column, and with its byte offset, which is easier
+// to seek to in a huge single line:
+//
+//	line 1, column 5 (byte offset 4): invalid number "1x"
+func IntFields(reader io.Reader) ([]int, error) {
+	// bufio.Reader reads large chunks from reader
+	// for us, while we read one byte at a time.
+	bufferedReader := bufio.NewReader(reader)
+
+	var ints []int
+
+	// Bytes of the integer we are reading
+	var token []byte
+
+	// Line and column of the current byte, and
+	// of the first byte of token.
+	lineNumber, column := 1, 0
+	tokenLine, tokenColumn := 0, 0
+
+	// Byte offset of the current byte, and of the
+	// first byte of token, where the first byte
+	// of reader is at offset 0.
+	offset := -1
+	tokenOffset := 0
+
+	// endToken converts token to an integer
+	// and appends it to ints.
+	endToken := func() error {
+		if len(token) == 0 {
+			return nil
+		}
+
+		i, err := strconv.Atoi(string(token))
+
+		if err != nil {
+			return fmt.Errorf("line %d, column %d (byte offset %d): invalid number %q", tokenLine, tokenColumn, tokenOffset, token)
+		}
+
+		ints = append(ints, i)
+		token = token[:0]
+
+		return nil
+	}
+
+	for {
+		b, err := bufferedReader.ReadByte()
+
+		if err == io.EOF {
+			if err := endToken(); err != nil {
+				return nil, err
+			}
+
+			return ints, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		column++
+		offset++
+
+		switch b {
+		case ' ', '\t', '\n', '\r', '\v', '\f':
+			if err := endToken(); err != nil {
+				return nil, err
+			}
+
+			if b == '\n' {
+				lineNumber++
+				column = 0
+			}
+		default:
+			if len(token) == 0 {
+				tokenLine, tokenColumn = lineNumber, column
+				tokenOffset = offset
+			}
+
+			token = append(token, b)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package parse
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestLines validates that Lines skips blank lines,
+// accepts "\r\n" line endings and keeps counting
+// the lines it skips.
+func TestLines(t *testing.T) {
+	lines, err := Lines(strings.NewReader("first\r\n\r\n  second  \r\n\n\tthird"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Line{
+		{Number: 1, Text: "first"},
+		{Number: 3, Text: "second"},
+		{Number: 5, Text: "third"},
+	}
+
+	if !slices.Equal(lines, expected) {
+		t.Errorf("lines are %v, expected %v", lines, expected)
+	}
+}
+
+// TestInts validates which "-" makes an integer
+// negative.
+func TestInts(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []int
+	}{
+		{"#13 @ 176,605: 24x11", []int{13, 176, 605, 24, 11}},
+		{"x=-3, y=-12", []int{-3, -12}},
+		{"-7", []int{-7}},
+		{"[1518-04-09 00:05] falls asleep", []int{1518, 4, 9, 0, 5}},
+		{"from 3--4", []int{3, -4}},
+		{"no numbers", nil},
+	}
+
+	for _, test := range tests {
+		ints, err := Ints(Line{Number: 1, Text: test.text})
+
+		if err != nil {
+			t.Errorf("%q: %v", test.text, err)
+		} else if !slices.Equal(ints, test.expected) {
+			t.Errorf("%q gives %v, expected %v", test.text, ints, test.expected)
+		}
+	}
+
+	if _, err := Ints(Line{Number: 4, Text: "x=99999999999999999999"}); err == nil || !strings.HasPrefix(err.Error(), "line 4: ") {
+		t.Errorf("too large integer gives error %v, expected one about line 4", err)
+	}
+}
+
+// TestPatternMatch validates that the error of a line
+// that does not match a pattern, contains the line
+// number and the example of the pattern.
+func TestPatternMatch(t *testing.T) {
+	p := MustCompile(`^(\d+), (\d+)$`, "300, 90")
+
+	groups, err := p.Match(Line{Number: 1, Text: "1, 2"})
+
+	if err != nil || !slices.Equal(groups, []string{"1", "2"}) {
+		t.Errorf("match is %q with error %v, expected [1 2]", groups, err)
+	}
+
+	_, err = p.Match(Line{Number: 7, Text: "1; 2"})
+
+	if expected := `line 7: expected a line like "300, 90", got "1; 2"`; err == nil || err.Error() != expected {
+		t.Errorf("error is %v, expected %q", err, expected)
+	}
+
+	// A pattern matches the whole line, even if
+	// the expression does not say so.
+	unanchored := MustCompile(`(\d+), (\d+)`, "300, 90")
+
+	if _, err := unanchored.Match(Line{Number: 1, Text: "1, 2, 3"}); err == nil {
+		t.Error("unanchored pattern matches part of a line")
+	}
+}
+
+// TestMustCompile validates that MustCompile panics
+// if the example does not match.
+func TestMustCompile(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustCompile accepted an example that does not match")
+		}
+	}()
+
+	MustCompile(`^\d+$`, "x")
+}
+
+// TestIntFields validates that IntFields accepts any
+// whitespace, and reports where an invalid number is.
+func TestIntFields(t *testing.T) {
+	ints, err := IntFields(strings.NewReader("  1 -2\r\n\t3  \n"))
+
+	if err != nil || !slices.Equal(ints, []int{1, -2, 3}) {
+		t.Errorf("integers are %v with error %v, expected [1 -2 3]", ints, err)
+	}
+
+	_, err = IntFields(strings.NewReader("1 2\n 3 1x 4"))
+
+	if expected := `line 2, column 4 (byte offset 7): invalid number "1x"`; err == nil || err.Error() != expected {
+		t.Errorf("error is %v, expected %q", err, expected)
+	}
+}